@@ -0,0 +1,37 @@
+package cmd
+
+import (
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	"github.com/snowdrop/spring-boot-cloud-devex/pkg/buildpack"
+)
+
+func init() {
+	var specFile string
+
+	applyCmd := &cobra.Command{
+		Use:     "apply -f spec.yaml",
+		Short:   "Recreate a deployment from a spec captured by 'sd init --save-spec'",
+		Long:    `Recreate a deployment from a spec captured by 'sd init --save-spec': the Application it contains is used as-is, with no flags, MANIFEST, or "auto" detection re-read, so the same spec file reproduces an identical environment on any cluster.`,
+		Example: " sd apply -f spec.yaml",
+		Args:    cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+
+			log.Info("Apply command called")
+
+			application, err := buildpack.ReadSpec(specFile)
+			if err != nil {
+				log.Fatalf("Unable to read spec '%s': %s", specFile, err.Error())
+			}
+
+			SetupFromApplication(application)
+		},
+	}
+
+	applyCmd.Flags().StringVarP(&specFile, "file", "f", "", "Spec file written by 'sd init --save-spec' (required)")
+	applyCmd.MarkFlagRequired("file")
+	applyCmd.Annotations = map[string]string{"command": "apply"}
+
+	rootCmd.AddCommand(applyCmd)
+}
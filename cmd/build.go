@@ -14,6 +14,10 @@ import (
 )
 
 func init() {
+	var deploy bool
+	var waitForRollout bool
+	var clean bool
+
 	buildCmd := &cobra.Command{
 		Use:     "build [flags]",
 		Short:   "Build an image of the application",
@@ -27,6 +31,10 @@ func init() {
 			setup := Setup()
 			log.Debugf("Namespace: %s", setup.Application.Namespace)
 
+			if clean {
+				setup.Application.Incremental = false
+			}
+
 			// Create Build
 			log.Info("Create Build resource")
 			buildpack.CreateBuild(setup.RestConfig, setup.Application)
@@ -46,9 +54,34 @@ func init() {
 			args = []string{"start-build", setup.Application.Name, "--from-dir=" + oc.Client.Pwd, "--follow"}
 			log.Infof("Start-build cmd : %s", args)
 			oc.ExecCommand(oc.Command{Args: args})
+
+			if deploy {
+				imageStreamTag := setup.Application.Name + ":latest"
+				workload, err := buildpack.GetWorkload(setup.RestConfig, setup.Clientset, setup.Application)
+				if err == buildpack.ErrNotInitialized {
+					log.Fatal("No DeploymentConfig or Deployment to deploy to. Run 'sd init' first")
+				} else if err != nil {
+					log.Fatalf("Unable to deploy build: %s", err.Error())
+				}
+				log.Infof("Updating workload to use '%s'", imageStreamTag)
+				if err := workload.SetImage(imageStreamTag); err != nil {
+					log.Fatalf("Unable to deploy build: %s", err.Error())
+				}
+
+				if waitForRollout {
+					log.Info("Waiting for the rollout to complete")
+					if err := workload.WaitForRollout(buildpack.DefaultRolloutTimeout); err != nil {
+						log.Fatalf("Rollout did not complete: %s", err.Error())
+					}
+				}
+			}
 		},
 	}
 
+	buildCmd.Flags().BoolVar(&deploy, "deploy", false, "Update the DeploymentConfig to the image produced by this build")
+	buildCmd.Flags().BoolVar(&waitForRollout, "wait", false, "With --deploy, block until the rollout completes (or fails) instead of returning immediately")
+	buildCmd.Flags().BoolVar(&clean, "clean", false, "Force a non-incremental build, overriding the MANIFEST's incremental setting. Only takes effect while the BuildConfig is first created; it won't make an already-existing BuildConfig's next run incremental or not")
+
 	// Add a defined annotation in order to appear in the help menu
 	buildCmd.Annotations = map[string]string{"command": "build"}
 
@@ -8,32 +8,65 @@ import (
 )
 
 func init() {
+	var gracePeriod int64
+	var force bool
+	var yes bool
+	var keepPVC bool
+
 	cleanCmd := &cobra.Command{
 		Use:     "clean [flags]",
 		Short:   "Remove development pod for the component",
-		Long:    `Remove development pod for the component.`,
-		Example: ` sd clean`,
+		Long:    `Remove development pod for the component. The pod is given --grace-period seconds to shut down cleanly (e.g. run JVM shutdown hooks) before being killed; --force skips straight to a grace period of 0 for a pod that's stuck.`,
+		Example: " sd clean\n sd clean --grace-period 60\n sd clean --force",
 		Args:    cobra.RangeArgs(0, 1),
 		Run: func(cmd *cobra.Command, args []string) {
 
 			log.Info("Clean command called")
 
+			if !confirmDestructive("This will delete the dev pod's DeploymentConfig, PVC, maven-settings ConfigMap, Service, Route, HorizontalPodAutoscaler and ImageStreams", yes || force) {
+				return
+			}
+
+			if force {
+				gracePeriod = 0
+			}
+
 			setup := Setup()
 
 			buildpack.DeleteDefaultImageStreams(setup.RestConfig, setup.Application)
 
-			buildpack.DeletePVC(setup.Clientset, setup.Application)
+			buildpack.DeleteDeploymentConfig(setup.RestConfig, setup.Application, gracePeriod)
+
+			if setup.Application.PVCName != "" && !cmd.Flags().Changed("keep-pvc") {
+				keepPVC = true
+			}
+			if keepPVC {
+				log.Infof("Keeping PVC '%s'", setup.Application.PVCName)
+			} else {
+				buildpack.DeletePVC(setup.Clientset, setup.Application)
+			}
 
-			buildpack.DeleteDeploymentConfig(setup.RestConfig, setup.Application)
+			buildpack.DeleteMavenSettingsConfigMap(setup.Clientset, setup.Application)
 
 			buildpack.DeleteService(setup.Clientset, setup.Application)
 
 			buildpack.DeleteRoute(setup.RestConfig, setup.Application)
 
+			buildpack.DeleteHPA(setup.Clientset, setup.Application)
+
+			if len(setup.Application.ExtraManifests) > 0 {
+				buildpack.DeleteExtraManifests(setup.Application.ExtraManifests)
+			}
+
 			log.Info("Deleted resources")
 		},
 	}
 
+	cleanCmd.Flags().Int64Var(&gracePeriod, "grace-period", 30, "Seconds to give the pod to shut down cleanly before it's killed")
+	cleanCmd.Flags().BoolVar(&force, "force", false, "Skip graceful shutdown (grace period 0) and skip the confirmation prompt")
+	cleanCmd.Flags().BoolVar(&yes, "yes", false, "Skip the confirmation prompt")
+	cleanCmd.Flags().BoolVar(&keepPVC, "keep-pvc", false, "Don't delete the m2 PVC. Defaults to true when the MANIFEST/--use-pvc PVC is a shared one, since other applications may still be using it")
+
 	// Add a defined annotation in order to appear in the help menu
 	cleanCmd.Annotations = map[string]string{"command": "clean"}
 
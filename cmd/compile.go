@@ -1,33 +1,108 @@
 package cmd
 
 import (
+	"fmt"
+	"os"
+	"os/signal"
+	"time"
+
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 
+	"github.com/snowdrop/spring-boot-cloud-devex/pkg/buildpack"
 	"github.com/snowdrop/spring-boot-cloud-devex/pkg/common/config"
 	"github.com/snowdrop/spring-boot-cloud-devex/pkg/common/oc"
 )
 
+// watchPollInterval and watchDebounce bound how quickly `sd compile --watch`
+// reacts to a save: polled this often, and held off this long after the
+// last change seen so a burst of saves from an IDE triggers one rebuild.
+const (
+	watchPollInterval = 1 * time.Second
+	watchDebounce     = 300 * time.Millisecond
+)
+
 func init() {
+	var watch bool
+
 	compileCmd := &cobra.Command{
 		Use:     "compile",
 		Short:   "Compile local project within the development pod",
-		Long:    `Compile local project within the development pod.`,
-		Example: ` sd compile`,
+		Long:    `Compile local project within the development pod. --watch keeps running, recompiling on every local source change until interrupted.`,
+		Example: " sd compile\n sd compile --watch",
 		Args:    cobra.RangeArgs(0, 1),
 		Run: func(cmd *cobra.Command, args []string) {
 
 			log.Info("Compile command called")
 
-			_, pod := SetupAndWaitForPod()
-			podName := pod.Name
+			setup, pod := SetupAndWaitForPod()
+			if !setup.Application.MountSource {
+				log.Fatal("'sd compile' requires mountSource (it builds via supervisord inside the pod); this dev pod runs its image as-is and has no build wiring")
+			}
+
+			if !watch {
+				if !compileOnce(pod.Name, setup.Application.BuildTool) {
+					os.Exit(1)
+				}
+				return
+			}
 
-			log.Info("Compile ...")
-			oc.ExecCommand(oc.Command{Args: []string{"rsh", podName, config.SupervisordBin, config.SupervisordCtl, "start", config.CompileCmdName}})
-			oc.ExecCommand(oc.Command{Args: []string{"logs", podName, "-f"}})
+			runCompileWatch(pod.Name, setup.Application.BuildTool)
 		},
 	}
 
+	compileCmd.Flags().BoolVar(&watch, "watch", false, "Recompile on every local source change until interrupted, instead of compiling once")
 	compileCmd.Annotations = map[string]string{"command": "compile"}
 	rootCmd.AddCommand(compileCmd)
 }
+
+// compileOnce triggers the in-pod build and streams its outcome, returning
+// false (after printing the failing build's tail logs) on a non-zero exit
+// code instead of calling log.Fatal, so --watch can keep running after a
+// broken compile instead of killing the whole command.
+func compileOnce(podName string, buildTool string) bool {
+	compileProgram, _ := config.CommandNames(buildTool)
+
+	log.Info("Compile ...")
+	oc.ExecCommand(oc.Command{Args: []string{"rsh", podName, config.SupervisordBin, config.SupervisordCtl, "start", compileProgram}})
+
+	exitCode, err := buildpack.WaitForProgramExit(podName, compileProgram)
+	if err != nil {
+		log.Fatalf("Unable to determine build status: %s", err.Error())
+	}
+	if exitCode != 0 {
+		fmt.Println(buildpack.TailLogs(podName))
+		log.Errorf("Build failed with exit code %d", exitCode)
+		return false
+	}
+
+	oc.ExecCommand(oc.Command{Args: []string{"logs", podName, "-f"}})
+	return true
+}
+
+// runCompileWatch compiles once, then recompiles every time WatchSource
+// reports a local source change, until Ctrl-C.
+func runCompileWatch(podName string, buildTool string) {
+	compileOnce(podName, buildTool)
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		log.Fatalf("Unable to determine working directory: %s", err.Error())
+	}
+
+	stop := make(chan struct{})
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, os.Interrupt)
+	go func() {
+		<-sigChan
+		close(stop)
+	}()
+
+	log.Info("Watching for source changes (Ctrl-C to stop)")
+	for range buildpack.WatchSource(cwd, stop, watchPollInterval, watchDebounce) {
+		log.Info("Source changed, recompiling ...")
+		compileOnce(podName, buildTool)
+	}
+
+	log.Info("Stopped watching")
+}
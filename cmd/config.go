@@ -0,0 +1,87 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	"github.com/snowdrop/spring-boot-cloud-devex/pkg/buildpack"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+func init() {
+	explainCmd := &cobra.Command{
+		Use:     "explain [flags]",
+		Short:   "Show where each resolved setting came from",
+		Long:    `Show where each resolved setting came from (a flag, the MANIFEST, the kubeconfig, or a built-in default), to debug reports like "why is it deploying to the wrong namespace". Read-only: doesn't touch the cluster or prompt for anything.`,
+		Example: "  sd config explain",
+		Args:    cobra.RangeArgs(0, 1),
+		Run: func(cmd *cobra.Command, args []string) {
+
+			log.Info("Config explain command called")
+
+			appConfig := parseManifest()
+			kubeCfg := getK8Config(*rootCmd)
+
+			explain := func(setting string, value string, source string) {
+				fmt.Printf("%-12s %-50s (%s)\n", setting+":", value, source)
+			}
+
+			name := appConfig.Name
+			nameSource := "MANIFEST"
+			if name == "" {
+				current, _ := os.Getwd()
+				name = path.Base(current)
+				nameSource = "current directory name (default; `sd init` would prompt to confirm/override)"
+			}
+			explain("App name", name, nameSource)
+
+			kubeconfigSource := "default ($HOME/.kube/config)"
+			if rootCmd.Flag("kubeconfig").Changed {
+				kubeconfigSource = "--kubeconfig"
+			}
+			explain("Kubeconfig", kubeCfg.Config, kubeconfigSource)
+
+			if rawConfig, err := clientcmd.LoadFromFile(kubeCfg.Config); err == nil {
+				explain("Context", rawConfig.CurrentContext, "kubeconfig's current-context")
+				if context, ok := rawConfig.Contexts[rawConfig.CurrentContext]; ok {
+					explain("Namespace", context.Namespace, "kubeconfig context's namespace (overridden at runtime by `oc project -q`, if logged in)")
+				}
+			} else {
+				explain("Context", "", fmt.Sprintf("could not read kubeconfig: %s", err.Error()))
+			}
+
+			if appConfig.DevImage != "" {
+				devImageSource := "MANIFEST"
+				if devImage != "" {
+					devImageSource = "--dev-image"
+				}
+				explain("Dev image", appConfig.DevImage, devImageSource)
+			} else {
+				explain("Image repos", "quay.io/snowdrop/spring-boot-s2i, quay.io/snowdrop/supervisord", "built-in default (S2I ImageStreams)")
+			}
+
+			portSource := "MANIFEST"
+			if appConfig.Port == 8080 {
+				portSource = "detected from server.port, or built-in default (8080)"
+			}
+			explain("Port", fmt.Sprintf("%d", appConfig.Port), portSource)
+
+			explain("Registry", buildpack.InternalRegistryServiceHost, "in-cluster default; run `sd doctor` for a live check of whether it's exposed externally via a Route")
+		},
+	}
+
+	configCmd := &cobra.Command{
+		Use:     "config [command]",
+		Short:   "Inspect sd's resolved configuration",
+		Long:    `Inspect sd's resolved configuration and, for each setting, which of its many sources (flags, MANIFEST, kubeconfig, cluster, built-in defaults) won.`,
+		Example: explainCmd.Example,
+	}
+	configCmd.AddCommand(explainCmd)
+	configCmd.Annotations = map[string]string{"command": "config"}
+
+	rootCmd.AddCommand(configCmd)
+}
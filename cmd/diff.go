@@ -0,0 +1,46 @@
+package cmd
+
+import (
+	"fmt"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/snowdrop/spring-boot-cloud-devex/pkg/buildpack"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	diffCmd := &cobra.Command{
+		Use:     "diff [flags]",
+		Short:   "Compare the desired resources against the live ones",
+		Long:    `Compare the DeploymentConfig, Service and Route that would be generated from the MANIFEST against what's currently live in the cluster.`,
+		Example: ` sd diff`,
+		Args:    cobra.RangeArgs(0, 1),
+		Run: func(cmd *cobra.Command, args []string) {
+
+			log.Info("Diff command called")
+
+			setup := Setup()
+
+			diffs := map[string]string{
+				"DeploymentConfig": buildpack.DiffDeploymentConfig(setup.RestConfig, setup.Application, "", noCache),
+				"Service":          buildpack.DiffService(setup.Clientset, setup.Application),
+				"Route":            buildpack.DiffRoute(setup.RestConfig, setup.Application),
+			}
+
+			changed := false
+			for _, kind := range []string{"DeploymentConfig", "Service", "Route"} {
+				if d := diffs[kind]; d != "" {
+					changed = true
+					fmt.Printf("--- %s ---\n%s\n", kind, d)
+				}
+			}
+
+			if !changed {
+				log.Info("No differences found")
+			}
+		},
+	}
+
+	diffCmd.Annotations = map[string]string{"command": "diff"}
+	rootCmd.AddCommand(diffCmd)
+}
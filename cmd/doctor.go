@@ -0,0 +1,67 @@
+package cmd
+
+import (
+	"fmt"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	appsv1 "github.com/openshift/api/apps/v1"
+	imagev1 "github.com/openshift/api/image/v1"
+	routev1 "github.com/openshift/api/route/v1"
+
+	"github.com/snowdrop/spring-boot-cloud-devex/pkg/buildpack"
+	"github.com/snowdrop/spring-boot-cloud-devex/pkg/common/config"
+	"github.com/snowdrop/spring-boot-cloud-devex/pkg/common/oc"
+)
+
+func init() {
+	doctorCmd := &cobra.Command{
+		Use:     "doctor [flags]",
+		Short:   "Diagnose common setup problems",
+		Long:    `Diagnose common setup problems: the oc client, kubeconfig, any exec-based credential plugin it configures, and whether the cluster serves the OpenShift APIs sd depends on.`,
+		Example: ` sd doctor`,
+		Args:    cobra.RangeArgs(0, 1),
+		Run: func(cmd *cobra.Command, args []string) {
+
+			log.Info("Doctor command called")
+
+			healthy := true
+			check := func(name string, err error) {
+				if err != nil {
+					healthy = false
+					fmt.Printf("FAIL %s: %s\n", name, err.Error())
+					return
+				}
+				fmt.Printf("OK   %s\n", name)
+			}
+
+			kubeCfg := getK8Config(*rootCmd)
+
+			if oc.Client.Path == "" {
+				check("oc client on $PATH", fmt.Errorf("not found; set %s to override its location", oc.OcBinEnvVar))
+			} else {
+				check("oc client on $PATH", nil)
+			}
+
+			check("kubeconfig auth plugin", config.CheckExecAuth(kubeCfg.Config))
+
+			restConfig := createKubeRestconfig(kubeCfg)
+			_, err := restConfig.TransportConfig()
+			check("kubeconfig builds a transport", err)
+
+			check("DeploymentConfig API served", config.RequireServedGroupVersion(restConfig, appsv1.SchemeGroupVersion.String(), "DeploymentConfig"))
+			check("ImageStream API served", config.RequireServedGroupVersion(restConfig, imagev1.SchemeGroupVersion.String(), "ImageStream"))
+			check("Route API served", config.RequireServedGroupVersion(restConfig, routev1.SchemeGroupVersion.String(), "Route"))
+
+			fmt.Printf("INFO internal registry: %s\n", buildpack.DiscoverInternalRegistryHost(restConfig))
+
+			if !healthy {
+				log.Fatal("One or more checks failed")
+			}
+		},
+	}
+
+	doctorCmd.Annotations = map[string]string{"command": "doctor"}
+	rootCmd.AddCommand(doctorCmd)
+}
@@ -0,0 +1,40 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/snowdrop/spring-boot-cloud-devex/pkg/buildpack"
+)
+
+func init() {
+	var list bool
+	var unset string
+
+	envCmd := &cobra.Command{
+		Use:     "env [flags]",
+		Short:   "List or modify the development pod's environment variables",
+		Long:    `List or modify the application container's environment variables, as currently deployed on the DeploymentConfig.`,
+		Example: "  sd env --list\n  sd env --unset MY_VAR",
+		Run: func(cmd *cobra.Command, args []string) {
+
+			setup := Setup()
+
+			if unset != "" {
+				buildpack.UnsetEnv(setup.RestConfig, setup.Application, unset)
+				return
+			}
+
+			for _, entry := range buildpack.ListEnv(setup.RestConfig, setup.Application) {
+				fmt.Printf("%s=%s (%s)\n", entry.Name, entry.Value, entry.Source)
+			}
+		},
+	}
+
+	envCmd.Flags().BoolVar(&list, "list", false, "List the container's current environment variables (also the default action)")
+	envCmd.Flags().StringVar(&unset, "unset", "", "Remove the given environment variable and roll out the DeploymentConfig")
+	envCmd.Annotations = map[string]string{"command": "env"}
+
+	rootCmd.AddCommand(envCmd)
+}
@@ -0,0 +1,59 @@
+package cmd
+
+import (
+	"fmt"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/snowdrop/spring-boot-cloud-devex/pkg/buildpack"
+)
+
+func init() {
+	var watch bool
+	var eventType string
+	var reason string
+
+	eventsCmd := &cobra.Command{
+		Use:     "events [flags]",
+		Short:   "List events in the dev pod's namespace",
+		Long:    `List events in the dev pod's namespace, optionally streaming new ones as they happen with --watch. --type and --reason narrow the output to events worth investigating (FailedMount, Unhealthy, BackOff, ...) instead of scrolling through routine Normal events.`,
+		Example: "  sd events\n  sd events --watch --type Warning\n  sd events --reason BackOff",
+		Args:    cobra.RangeArgs(0, 1),
+		Run: func(cmd *cobra.Command, args []string) {
+
+			log.Info("Events command called")
+
+			setup := Setup()
+			filter := buildpack.EventFilter{Type: eventType, Reason: reason}
+
+			if watch {
+				if err := buildpack.WatchEvents(setup.Clientset, setup.Application, filter, printEvent); err != nil {
+					log.Fatal(err.Error())
+				}
+				return
+			}
+
+			events, err := buildpack.ListEvents(setup.Clientset, setup.Application, filter)
+			if err != nil {
+				log.Fatal(err.Error())
+			}
+			for _, event := range events {
+				printEvent(event)
+			}
+		},
+	}
+
+	eventsCmd.Flags().BoolVarP(&watch, "watch", "w", false, "Stream new events instead of just listing what's there")
+	eventsCmd.Flags().StringVar(&eventType, "type", "", "Only show events of this type (e.g. Warning)")
+	eventsCmd.Flags().StringVar(&reason, "reason", "", "Only show events whose reason contains this substring (e.g. FailedMount)")
+	eventsCmd.Annotations = map[string]string{"command": "events"}
+
+	rootCmd.AddCommand(eventsCmd)
+}
+
+func printEvent(event corev1.Event) {
+	fmt.Printf("%s\t%s\t%s\t%s\n", event.Type, event.Reason, event.InvolvedObject.Name, event.Message)
+}
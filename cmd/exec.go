@@ -5,6 +5,7 @@ import (
 	"github.com/spf13/cobra"
 
 	"fmt"
+	"github.com/snowdrop/spring-boot-cloud-devex/pkg/buildpack"
 	"github.com/snowdrop/spring-boot-cloud-devex/pkg/common/config"
 	"github.com/snowdrop/spring-boot-cloud-devex/pkg/common/oc"
 	"strings"
@@ -14,7 +15,7 @@ func newCommand(action string) *cobra.Command {
 	return newCommandWith(action, execAction)
 }
 
-func newCommandWith(action string, toExec func(podName string, action string)) *cobra.Command {
+func newCommandWith(action string, toExec func(podName string, containerName string, action string, runCmd string)) *cobra.Command {
 	capitalizedAction := strings.Title(action)
 
 	return &cobra.Command{
@@ -27,34 +28,42 @@ func newCommandWith(action string, toExec func(podName string, action string)) *
 
 			log.Infof("Exec %s command called", action)
 
-			_, pod := SetupAndWaitForPod()
+			setup, pod := SetupAndWaitForPod()
 			podName := pod.Name
+			containerName := buildpack.ContainerName(setup.Application)
+			_, runCmd := config.CommandNames(setup.Application.BuildTool)
 
 			log.Infof("%s the Spring Boot application ...", capitalizedAction)
-			toExec(podName, action)
-			oc.ExecCommand(oc.Command{Args: []string{"logs", podName, "-f"}})
+			toExec(podName, containerName, action, runCmd)
+			oc.ExecCommand(oc.Command{Args: []string{"logs", podName, "-c", containerName, "-f"}})
 		},
 	}
 }
 
-func execAction(podName string, action string) {
-	cmdArgs := []string{"rsh", podName, config.SupervisordBin, config.SupervisordCtl, action, config.RunCmdName}
+func execAction(podName string, containerName string, action string, runCmd string) {
+	cmdArgs := []string{"rsh", "-c", containerName, podName, config.SupervisordBin, config.SupervisordCtl, action, runCmd}
 	log.Debug("Command :", cmdArgs)
 	oc.ExecCommand(oc.Command{Args: cmdArgs})
 }
 
+// restartApplication stops then starts runCmd under supervisord, the same
+// sequence `sd exec restart` runs; `sd push --auto-restart` calls it
+// directly instead of shelling out to that subcommand.
+func restartApplication(podName string, containerName string, runCmd string) {
+	oc.ExecCommand(oc.Command{Args: []string{"rsh", "-c", containerName, podName, config.SupervisordBin, config.SupervisordCtl, "stop", runCmd}})
+	oc.ExecCommand(oc.Command{Args: []string{"rsh", "-c", containerName, podName, config.SupervisordBin, config.SupervisordCtl, "start", runCmd}})
+}
+
 func init() {
 	var ports string
 
 	execStartCmd := newCommand("start")
 	execStopCmd := newCommand("stop")
-	execRestartCmd := newCommandWith("restart", func(podName string, action string) {
-		oc.ExecCommand(oc.Command{Args: []string{"rsh", podName, config.SupervisordBin, config.SupervisordCtl, "stop", config.RunCmdName}})
-		oc.ExecCommand(oc.Command{Args: []string{"rsh", podName, config.SupervisordBin, config.SupervisordCtl, "start", config.RunCmdName}})
+	execRestartCmd := newCommandWith("restart", func(podName string, containerName string, action string, runCmd string) {
+		restartApplication(podName, containerName, runCmd)
 	})
-	execDebugCmd := newCommandWith("debug", func(podName string, action string) {
-		oc.ExecCommand(oc.Command{Args: []string{"rsh", podName, config.SupervisordBin, config.SupervisordCtl, "stop", config.RunCmdName}})
-		oc.ExecCommand(oc.Command{Args: []string{"rsh", podName, config.SupervisordBin, config.SupervisordCtl, "start", config.RunCmdName}})
+	execDebugCmd := newCommandWith("debug", func(podName string, containerName string, action string, runCmd string) {
+		restartApplication(podName, containerName, runCmd)
 
 		// Forward local to Remote port
 		log.Info("Remote Debug the Spring Boot Application ...")
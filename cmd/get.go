@@ -0,0 +1,82 @@
+package cmd
+
+import (
+	"fmt"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	"github.com/snowdrop/spring-boot-cloud-devex/pkg/buildpack"
+)
+
+func init() {
+	var url bool
+
+	getRouteCmd := &cobra.Command{
+		Use:     "route [flags]",
+		Short:   "Get the application's Route",
+		Long:    `Get the application's Route. With --url, print only its scheme + host, with no log noise, for use in scripts.`,
+		Example: "  sd get route --url",
+		Args:    cobra.RangeArgs(0, 1),
+		Run: func(cmd *cobra.Command, args []string) {
+
+			setup := Setup()
+
+			routes, err := buildpack.GetRoutes(setup.RestConfig, setup.Application)
+			if err != nil {
+				log.Fatalf("Unable to retrieve Routes: %s", err.Error())
+			}
+			if len(routes) == 0 {
+				log.Fatal("No Route exists yet")
+			}
+
+			for _, route := range routes {
+				if !url {
+					log.Infof("Route '%s' -> %s", route.Name, route.Spec.Host)
+					continue
+				}
+
+				routeURL, err := buildpack.RouteURL(route)
+				if err != nil {
+					log.Fatal(err.Error())
+				}
+				fmt.Println(routeURL)
+			}
+		},
+	}
+	getRouteCmd.Flags().BoolVar(&url, "url", false, "Print only the Route's URL (scheme + host), with no log noise; exits non-zero if it isn't admitted yet")
+
+	getStatusCmd := &cobra.Command{
+		Use:     "status [flags]",
+		Short:   "Show the DeploymentConfig's rollout status",
+		Long:    `Show the DeploymentConfig's replica counts and whether its rollouts are paused (see "sd rollout pause").`,
+		Example: "  sd get status",
+		Args:    cobra.RangeArgs(0, 1),
+		Run: func(cmd *cobra.Command, args []string) {
+			setup := Setup()
+
+			dc, err := buildpack.GetDeploymentConfig(setup.RestConfig, setup.Application)
+			if err != nil {
+				log.Fatalf("Unable to retrieve DeploymentConfig: %s", err.Error())
+			}
+
+			rolloutState := "active"
+			if dc.Spec.Paused {
+				rolloutState = "paused"
+			}
+			fmt.Printf("DeploymentConfig '%s': %d/%d replicas available, rollouts %s\n", dc.Name, dc.Status.AvailableReplicas, dc.Spec.Replicas, rolloutState)
+		},
+	}
+
+	getCmd := &cobra.Command{
+		Use:     "get [resource]",
+		Short:   "Get information about the application's resources",
+		Long:    `Get information about the application's resources.`,
+		Example: getRouteCmd.Example,
+	}
+	getCmd.AddCommand(getRouteCmd)
+	getCmd.AddCommand(getStatusCmd)
+
+	getCmd.Annotations = map[string]string{"command": "get"}
+	rootCmd.AddCommand(getCmd)
+}
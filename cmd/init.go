@@ -0,0 +1,298 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+
+	"github.com/snowdrop/spring-boot-cloud-devex/pkg/buildpack"
+	log "github.com/sirupsen/logrus"
+	"github.com/snowdrop/spring-boot-cloud-devex/pkg/common/oc"
+	"github.com/spf13/cobra"
+)
+
+// noCache disables mounting the m2 PVC cache. It's read by
+// finishSetupAndSetApplicationName in root.go since the dev pod is
+// provisioned lazily the first time Setup() runs, not just from `sd init`.
+var noCache bool
+
+// outputManifests, when non-empty, makes `sd init` additionally render the
+// DeploymentConfig/Service/Route to this directory for GitOps workflows.
+var outputManifests string
+
+// devImage, when set, overrides the MANIFEST's devImage and makes the dev
+// pod run that prebuilt image directly instead of the S2I ImageStreams.
+var devImage string
+
+// extraLabels holds the raw "key=value" pairs from repeatable `--label`
+// flags, merged over the MANIFEST's labels by parseManifest.
+var extraLabels []string
+
+// arch, when set, overrides the MANIFEST's arch and Setup()'s node
+// detection, selecting which image variant CreateDefaultImageStreams uses.
+var arch string
+
+// mountSource overrides the MANIFEST's mountSource when set to "true" or
+// "false" ("" leaves the MANIFEST/default alone, same sentinel convention as
+// devImage/arch above). "false" drops the supervisord wiring from the DC
+// for an image-only dev pod; push, compile, and run all refuse to run in
+// that mode.
+var mountSource string
+
+// showPlan makes `sd init` print a concise ordered list of the actions it
+// would take (CREATE/UPDATE/SKIP per resource, derived from the same
+// exists/diff checks finishSetupAndSetApplicationName uses) and exit without
+// creating or updating anything. Unlike --output-manifests, it never renders
+// full YAML -- just a human-readable confirmation step before a real run.
+var showPlan bool
+
+// attachExisting makes `sd init` adopt a DeploymentConfig/Service/Route
+// that already exists under the application's name but wasn't labeled by
+// sd (created by hand or another tool), by applying OdoLabelName/Value to
+// them, instead of creating its own resources.
+var attachExisting bool
+
+// gitURL switches `sd init` to the Git-source build strategy: an S2I
+// BuildConfig builds this repository into the dev pod's image instead of
+// syncing local files via supervisord. It's a distinct strategy from
+// MountSource/DevImage -- push, compile, and run all refuse to run against
+// a Git-source dev pod, the same way they refuse to run against MountSource
+// false.
+var gitURL string
+
+// gitRef is the branch/tag/commit to build, used only when gitURL is set.
+// Empty means the repository's default branch.
+var gitRef string
+
+// replicas overrides the MANIFEST's replicas field when >= 0; -1 (the flag
+// default) leaves the MANIFEST/default alone, the same sentinel convention
+// as mountSource above but using an out-of-range int instead of "".
+var replicas int
+
+// mavenSettings, when set, overrides the MANIFEST's mavenSettings with the
+// local path to a settings.xml mounted into the dev pod as the
+// "maven-settings" ConfigMap, for corporate Maven mirrors/proxies.
+var mavenSettings string
+
+// setImage, when set, patches the DeploymentConfig (after it's created) to
+// run this image instead of the S2I/Git build's output -- for an image
+// produced out-of-band, e.g. by a CI pipeline. The reference is tagged into
+// the application's own ImageStreamTag so the DC's ImageChange trigger still
+// fires on future re-tags.
+var setImage string
+
+// outputFormat, when set to "json", makes `sd init` additionally print a
+// machine-readable InitResult after provisioning, so CI can extract the
+// Route URL for a smoke test without scraping log output. The default ""
+// leaves the existing human-readable log output as the only output.
+var outputFormat string
+
+// usePVC, when set, makes the dev pod mount this already-existing PVC as
+// its m2 cache instead of CreatePVC provisioning a new "m2-data" one, for
+// teams sharing a single pre-provisioned cache PVC across applications.
+// Setup() validates it exists and has a compatible access mode rather than
+// creating it.
+var usePVC string
+
+// annotationsFile, when set, is a local YAML/JSON file holding a flat map
+// of pod annotations merged over the MANIFEST's podAnnotations, for
+// environment-specific policy (Istio sidecar injection, backup policies,
+// ...) that shouldn't live in the committed MANIFEST.
+var annotationsFile string
+
+// probePreset, when set, overrides the MANIFEST's probePreset outright:
+// "springboot2" (the single combined /actuator/health endpoint),
+// "springboot3" (the split readiness/liveness health groups), "legacy"
+// (the pre-Actuator-2 "/health" endpoint), or "auto" to detect the
+// generation from pom.xml/build.gradle.
+var probePreset string
+
+// saveSpec, when set, writes the fully-resolved Application -- the same
+// data `sd init` just used to provision everything, post-flag-merge and
+// post-detection -- to this path, for `sd apply -f` to recreate an
+// identical environment elsewhere without re-reading flags or a MANIFEST.
+var saveSpec string
+
+func init() {
+	initCmd := &cobra.Command{
+		Use:     "init [flags]",
+		Short:   "Initialize the development pod and its resources",
+		Long:    `Initialize the development pod and its resources (ImageStreams, PVC, DeploymentConfig, Service, Route).`,
+		Example: ` sd init`,
+		Args:    cobra.RangeArgs(0, 1),
+		Run: func(cmd *cobra.Command, args []string) {
+
+			log.Info("Init command called")
+
+			if outputFormat != "" && outputFormat != "json" {
+				log.Fatalf("--output: unknown format '%s', must be 'json'", outputFormat)
+			}
+
+			if gitURL != "" {
+				runGitInit()
+				return
+			}
+
+			if showPlan {
+				printPlan()
+				return
+			}
+
+			if attachExisting {
+				runAttachExisting()
+				return
+			}
+
+			setup := Setup()
+
+			if outputManifests != "" {
+				log.Infof("Writing rendered manifests to '%s'", outputManifests)
+				if err := buildpack.WriteManifests(outputManifests, setup.Application, "", noCache); err != nil {
+					log.Fatalf("Unable to write manifests: %s", err.Error())
+				}
+			}
+
+			if setImage != "" {
+				log.Infof("Setting DeploymentConfig to run '%s' instead of the build output", setImage)
+				if err := buildpack.SetImage(setup.RestConfig, setup.Application, setImage); err != nil {
+					log.Fatalf("Unable to set image: %s", err.Error())
+				}
+			}
+
+			if saveSpec != "" {
+				log.Infof("Writing resolved deployment spec to '%s'", saveSpec)
+				if err := buildpack.WriteSpec(saveSpec, setup.Application); err != nil {
+					log.Fatalf("Unable to write spec: %s", err.Error())
+				}
+			}
+
+			if outputFormat == "json" {
+				result := buildpack.BuildInitResult(setup.RestConfig, setup.Clientset, setup.Application)
+				encoded, err := json.MarshalIndent(result, "", "  ")
+				if err != nil {
+					log.Fatalf("Unable to marshal result: %s", err.Error())
+				}
+				fmt.Println(string(encoded))
+			}
+		},
+	}
+
+	initCmd.Flags().BoolVar(&noCache, "no-cache", false, "Skip creating the m2 PVC cache, for ephemeral CI builds")
+	initCmd.Flags().StringVar(&outputManifests, "output-manifests", "", "Also write the rendered DeploymentConfig/Service/Route to this directory, as DIR/<kind>-<name>.yaml")
+	initCmd.Flags().StringVar(&devImage, "dev-image", "", "Use a prebuilt image for the dev pod instead of the S2I ImageStreams; it must already contain the JDK, build tool, and supervisord")
+	initCmd.Flags().StringArrayVar(&extraLabels, "label", []string{}, "key=value label to merge into all generated resources (repeatable). Cannot override the reserved io.openshift.odo label")
+	initCmd.Flags().StringVar(&arch, "arch", "", "Architecture of the image variant to deploy (amd64, arm64). Detected from a cluster node if unset")
+	initCmd.Flags().StringVar(&mountSource, "mount-source", "", "Set to 'false' for an image-only dev pod that runs --dev-image as-is, with no supervisord/source-sync wiring. push/compile/run refuse to run in this mode")
+	initCmd.Flags().BoolVar(&showPlan, "show-plan", false, "Print the CREATE/UPDATE/SKIP/VALIDATE actions sd init would take and exit, without creating or updating anything")
+	initCmd.Flags().BoolVar(&attachExisting, "attach-existing", false, "Adopt a DeploymentConfig/Service/Route already named after this application, by labeling it for sd to manage, instead of creating new resources")
+	initCmd.Flags().StringVar(&gitURL, "git-url", "", "Build the dev pod's image from this Git repository with an S2I BuildConfig, instead of syncing local files. A distinct strategy from --dev-image/--mount-source; push/compile/run refuse to run against it")
+	initCmd.Flags().StringVar(&gitRef, "git-ref", "", "Branch, tag, or commit to build from --git-url. Defaults to the repository's default branch")
+	initCmd.Flags().IntVar(&replicas, "replicas", -1, "Number of replicas for the DeploymentConfig. Defaults to the MANIFEST's replicas field (1 if unset). The m2-data PVC is ReadWriteOnce, so more than one only schedules with --no-cache")
+	initCmd.Flags().StringVar(&setImage, "set-image", "", "After creating the standard resources, patch the DeploymentConfig to run this image instead of the S2I/Git build output, tagging it into the application's own ImageStream so the DC's triggers still work")
+	initCmd.Flags().StringVar(&mavenSettings, "maven-settings", "", "Local path to a settings.xml mounted into the dev pod as the 'maven-settings' ConfigMap, for a corporate Maven mirror/proxy/credentials")
+	initCmd.Flags().StringVar(&outputFormat, "output", "", "Print a machine-readable result after provisioning. Only 'json' is supported")
+	initCmd.Flags().StringVar(&usePVC, "use-pvc", "", "Mount this already-existing PVC as the m2 cache instead of creating a new one, for a PVC shared across applications. Validated to exist with a compatible access mode. 'sd clean --keep-pvc' defaults to true when this is set")
+	initCmd.Flags().StringVar(&annotationsFile, "annotations-from-file", "", "Local path to a YAML/JSON file holding a flat map of pod annotations, merged over the MANIFEST's podAnnotations (the file wins on a key both declare)")
+	initCmd.Flags().StringVar(&probePreset, "probe-preset", "", "Readiness/liveness probe paths to render: springboot2 (default; single /actuator/health), springboot3 (split health groups), legacy (/health), or auto to detect the generation from pom.xml/build.gradle")
+	initCmd.Flags().StringVar(&saveSpec, "save-spec", "", "Write the fully-resolved deployment spec (post-flag-merge, post-detection) to this file, for 'sd apply -f' to recreate it elsewhere")
+	initCmd.Annotations = map[string]string{"command": "init"}
+
+	rootCmd.AddCommand(initCmd)
+}
+
+// printPlan resolves just enough of Setup() to know what sd init would do --
+// MANIFEST, kube config, namespace, and clientset -- without the interactive
+// name prompt or any of finishSetupAndSetApplicationName's side effects, then
+// prints buildpack.BuildPlan's CREATE/UPDATE/SKIP/VALIDATE steps.
+func printPlan() {
+	application := parseManifest()
+	kubeCfg := getK8Config(*rootCmd)
+	application.Namespace = resolveNamespace(kubeCfg, application.Namespace)
+	restConfig := createKubeRestconfig(kubeCfg)
+	clientset := createClientSet(kubeCfg, restConfig)
+
+	labelName, labelValue := discoveryLabel()
+	existingDCs, err := buildpack.GetNamesByLabel(restConfig, application.Namespace, labelName, labelValue)
+	if err != nil {
+		log.Fatalf("Error retrieving DeploymentConfig labeled %s=%s. Are you logged in?", labelName, labelValue)
+	}
+	if len(existingDCs) != 0 {
+		application.Name = existingDCs[0]
+	} else if application.Name == "" {
+		current, _ := os.Getwd()
+		application.Name = path.Base(current)
+	}
+
+	for _, step := range buildpack.BuildPlan(restConfig, clientset, application, application.StartCommand, noCache) {
+		fmt.Println(step.String())
+	}
+}
+
+// runGitInit sets up the Git-source build strategy: an S2I BuildConfig
+// builds --git-url (at --git-ref) into the application's own ImageStream,
+// then a DeploymentConfig/Service/Route are created the same way
+// finishSetupAndSetApplicationName does, watching that ImageStreamTag for
+// rollouts instead of supervisord's source-sync image. There's no local
+// file sync here, so push/compile/run refuse to run against the result.
+func runGitInit() {
+	application := parseManifest()
+
+	if application.Name == "" {
+		current, _ := os.Getwd()
+		application.Name = path.Base(current)
+	}
+
+	kubeCfg := getK8Config(*rootCmd)
+	application.Namespace = resolveNamespace(kubeCfg, application.Namespace)
+	restConfig := createKubeRestconfig(kubeCfg)
+	clientset := createClientSet(kubeCfg, restConfig)
+
+	log.Infof("Using Git-source build strategy: '%s' (ref '%s')", application.GitURL, application.GitRef)
+
+	log.Info("Create dev-s2i builder ImageStream, output ImageStream, and BuildConfig")
+	buildpack.CreateGitBuildConfig(restConfig, application)
+
+	log.Info("Starting the build")
+	oc.ExecCommand(oc.Command{Args: []string{"start-build", application.Name, "--follow"}})
+
+	log.Info("Create or retrieve DeploymentConfig watching the build's output")
+	dc := buildpack.CreateOrRetrieveDeploymentConfig(restConfig, application, "", true)
+
+	log.Info("Create Service using Template")
+	buildpack.CreateServiceTemplate(clientset, dc, application)
+
+	log.Info("Create Route using Template")
+	routes, errRoutes := buildpack.CreateRouteTemplate(restConfig, application)
+	if errRoutes != nil {
+		log.Fatalf("Unable to create Route: %s", errRoutes.Error())
+	}
+	for _, route := range routes {
+		if routeURL, err := buildpack.RouteURL(route); err == nil {
+			log.Infof("Route '%s' available at %s", route.Name, routeURL)
+		}
+	}
+}
+
+// runAttachExisting resolves the application name the same way printPlan
+// does, but from the MANIFEST/directory rather than an existing label
+// (the whole point is that the resource isn't labeled yet), then hands off
+// to buildpack.AttachExisting to adopt it.
+func runAttachExisting() {
+	application := parseManifest()
+	if application.Name == "" {
+		current, _ := os.Getwd()
+		application.Name = path.Base(current)
+	}
+
+	kubeCfg := getK8Config(*rootCmd)
+	application.Namespace = resolveNamespace(kubeCfg, application.Namespace)
+	restConfig := createKubeRestconfig(kubeCfg)
+	clientset := createClientSet(kubeCfg, restConfig)
+
+	if err := buildpack.AttachExisting(restConfig, clientset, application); err != nil {
+		log.Fatalf("Unable to attach existing resources: %s", err.Error())
+	}
+
+	log.Infof("'%s' is now managed by sd", application.Name)
+}
@@ -0,0 +1,149 @@
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"os/signal"
+	"sync"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	"github.com/snowdrop/spring-boot-cloud-devex/pkg/buildpack"
+	"github.com/snowdrop/spring-boot-cloud-devex/pkg/common/oc"
+)
+
+// containerLogRestartDelay is how long streamAllContainerLogs waits before
+// re-attaching to a container whose `oc logs -f` exited -- most often
+// because the container itself restarted -- so a crash-looping sidecar
+// doesn't spin the retry loop.
+const containerLogRestartDelay = 2 * time.Second
+
+// containerLogColors cycles through ANSI foreground colors so each
+// container's prefix in `sd logs --all-containers` is visually distinct;
+// it repeats if the pod has more containers than colors.
+var containerLogColors = []string{"36", "33", "32", "35", "34", "31"}
+
+func init() {
+	var follow bool
+	var containerInit bool
+	var allContainers bool
+
+	logsCmd := &cobra.Command{
+		Use:     "logs [flags]",
+		Short:   "View logs from the development pod",
+		Long:    `View logs from the development pod's main container, or its supervisord init container with --container-init (useful while it's still copying supervisord in, or after it failed). --all-containers streams every container (init and sidecars included) at once, each line prefixed with its container name.`,
+		Example: "  sd logs -f\n  sd logs --container-init\n  sd logs --all-containers -f",
+		Args:    cobra.RangeArgs(0, 1),
+		Run: func(cmd *cobra.Command, args []string) {
+
+			log.Info("Logs command called")
+
+			setup, pod := SetupAndWaitForPod()
+			podName := pod.Name
+
+			if allContainers {
+				containers := append(append([]v1.Container{}, pod.Spec.InitContainers...), pod.Spec.Containers...)
+				streamAllContainerLogs(podName, containers, follow, colorsDisabled(cmd))
+				return
+			}
+
+			containerName := buildpack.ContainerName(setup.Application)
+			if containerInit {
+				containerName = setup.Application.SupervisordName
+			}
+
+			cmdArgs := []string{"logs", podName, "-c", containerName}
+			if follow {
+				cmdArgs = append(cmdArgs, "-f")
+			}
+
+			oc.ExecCommand(oc.Command{Args: cmdArgs})
+		},
+	}
+
+	logsCmd.Flags().BoolVarP(&follow, "follow", "f", false, "Stream logs instead of just dumping what's there")
+	logsCmd.Flags().BoolVar(&containerInit, "container-init", false, "View the supervisord init container's logs instead of the main container's")
+	logsCmd.Flags().BoolVar(&allContainers, "all-containers", false, "Stream every container in the pod (init and sidecars included) concurrently, each line prefixed with its container name and color. Ignores --container-init")
+	logsCmd.Annotations = map[string]string{"command": "logs"}
+
+	rootCmd.AddCommand(logsCmd)
+}
+
+// streamAllContainerLogs runs `oc logs` for every container in containers
+// concurrently, writing each line to stdout prefixed with its container
+// name in a per-container color (unless noColor), until interrupted. When
+// follow is set, a container whose `oc logs -f` exits -- typically because
+// the container restarted -- is re-attached after containerLogRestartDelay
+// instead of being treated as the end of its logs.
+func streamAllContainerLogs(podName string, containers []v1.Container, follow bool, noColor bool) {
+	ctx, cancel := context.WithCancel(context.Background())
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, os.Interrupt)
+	go func() {
+		<-sigChan
+		cancel()
+	}()
+
+	var wg sync.WaitGroup
+	var writeMu sync.Mutex
+
+	for i, container := range containers {
+		prefix := fmt.Sprintf("[%s]", container.Name)
+		if !noColor {
+			prefix = fmt.Sprintf("\x1b[%sm%s\x1b[0m", containerLogColors[i%len(containerLogColors)], prefix)
+		}
+
+		wg.Add(1)
+		go func(containerName string, prefix string) {
+			defer wg.Done()
+
+			for {
+				args := []string{"logs", podName, "-c", containerName}
+				if follow {
+					args = append(args, "-f")
+				}
+
+				cmd := exec.CommandContext(ctx, oc.Client.Path, args...)
+				stdout, err := cmd.StdoutPipe()
+				if err != nil {
+					log.Errorf("--all-containers: '%s': %s", containerName, err.Error())
+					return
+				}
+				cmd.Stderr = os.Stderr
+
+				if err := cmd.Start(); err != nil {
+					log.Errorf("--all-containers: '%s': %s", containerName, err.Error())
+					return
+				}
+
+				scanner := bufio.NewScanner(stdout)
+				scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+				for scanner.Scan() {
+					writeMu.Lock()
+					fmt.Println(prefix, scanner.Text())
+					writeMu.Unlock()
+				}
+				cmd.Wait()
+
+				if !follow || ctx.Err() != nil {
+					return
+				}
+
+				select {
+				case <-ctx.Done():
+					return
+				case <-time.After(containerLogRestartDelay):
+				}
+			}
+		}(container.Name, prefix)
+	}
+
+	wg.Wait()
+}
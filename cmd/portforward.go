@@ -0,0 +1,54 @@
+package cmd
+
+import (
+	"os"
+	"os/signal"
+	"regexp"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/snowdrop/spring-boot-cloud-devex/pkg/buildpack"
+	"github.com/spf13/cobra"
+)
+
+var portPairRegexp = regexp.MustCompile(`^[0-9]+:[0-9]+$`)
+
+func init() {
+	portForwardCmd := &cobra.Command{
+		Use:     "port-forward LOCAL:REMOTE [LOCAL:REMOTE...]",
+		Short:   "Forward one or more local ports to the development pod",
+		Long:    `Forward one or more local ports to the development pod, keeping the connection open until interrupted.`,
+		Example: ` sd port-forward 8080:8080 5005:5005`,
+		Args:    cobra.MinimumNArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+
+			for _, pair := range args {
+				if !portPairRegexp.MatchString(pair) {
+					log.Fatalf("'%s' is not a valid LOCAL:REMOTE port pair", pair)
+				}
+			}
+
+			log.Info("Port-forward command called")
+
+			setup, pod := SetupAndWaitForPod()
+
+			log.Infof("Forwarding %v to pod '%s'", args, pod.Name)
+
+			stopChan := make(chan struct{}, 1)
+			readyChan := make(chan struct{})
+
+			sigChan := make(chan os.Signal, 1)
+			signal.Notify(sigChan, os.Interrupt)
+			go func() {
+				<-sigChan
+				close(stopChan)
+			}()
+
+			if err := buildpack.PortForward(setup.RestConfig, setup.Clientset, pod, args, stopChan, readyChan); err != nil {
+				log.Fatalf("Port-forward error: %s", err)
+			}
+		},
+	}
+
+	portForwardCmd.Annotations = map[string]string{"command": "port-forward"}
+	rootCmd.AddCommand(portForwardCmd)
+}
@@ -0,0 +1,65 @@
+package cmd
+
+import (
+	"os"
+	"runtime/pprof"
+	"runtime/trace"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// profilePath, when set via the hidden --profile flag, makes every command
+// write a Go CPU profile to this file for its whole duration, for tracking
+// down slow commands (e.g. `sd init` against a large cluster) with
+// `go tool pprof`.
+var profilePath string
+
+// tracePath, when set via the hidden --trace flag, additionally records a
+// Go execution trace alongside the CPU profile, viewable with
+// `go tool trace`.
+var tracePath string
+
+// startProfiling opens profilePath/tracePath (if set) and begins recording,
+// returning a stop func that flushes and closes them. It's registered with
+// logrus.RegisterExitHandler so profiles are still written when a command
+// exits via log.Fatal, which calls os.Exit directly and would otherwise skip
+// any deferred stop().
+func startProfiling() func() {
+	var profileFile, traceFile *os.File
+
+	if profilePath != "" {
+		f, err := os.Create(profilePath)
+		if err != nil {
+			log.Fatalf("Unable to create CPU profile '%s': %s", profilePath, err.Error())
+		}
+		if err := pprof.StartCPUProfile(f); err != nil {
+			log.Fatalf("Unable to start CPU profile: %s", err.Error())
+		}
+		profileFile = f
+	}
+
+	if tracePath != "" {
+		f, err := os.Create(tracePath)
+		if err != nil {
+			log.Fatalf("Unable to create trace '%s': %s", tracePath, err.Error())
+		}
+		if err := trace.Start(f); err != nil {
+			log.Fatalf("Unable to start trace: %s", err.Error())
+		}
+		traceFile = f
+	}
+
+	stop := func() {
+		if profileFile != nil {
+			pprof.StopCPUProfile()
+			profileFile.Close()
+		}
+		if traceFile != nil {
+			trace.Stop()
+			traceFile.Close()
+		}
+	}
+
+	log.RegisterExitHandler(stop)
+	return stop
+}
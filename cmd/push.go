@@ -3,20 +3,242 @@ package cmd
 import (
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
+	"golang.org/x/time/rate"
 	"strings"
+	"sync"
+	"time"
 
 	"fmt"
+	"github.com/snowdrop/spring-boot-cloud-devex/pkg/buildpack"
+	"github.com/snowdrop/spring-boot-cloud-devex/pkg/buildpack/types"
+	"github.com/snowdrop/spring-boot-cloud-devex/pkg/common/config"
 	"github.com/snowdrop/spring-boot-cloud-devex/pkg/common/oc"
 	"io/ioutil"
+	"k8s.io/api/core/v1"
 	"os"
 	"path/filepath"
 )
 
+// pushMarkerPath is touched in the pod before copying files and removed
+// once the copy finishes, so a push interrupted mid-sync (e.g. by a flaky
+// network) can be detected and warned about on the next run instead of
+// silently leaving the pod's source half-updated.
+const pushMarkerPath = "/tmp/.sd-push-in-progress"
+
+func markPushStart(podName string, containerName string) {
+	oc.ExecCommand(oc.Command{Args: []string{"rsh", "-c", containerName, podName, "touch", pushMarkerPath}})
+}
+
+func markPushComplete(podName string, containerName string) {
+	oc.ExecCommand(oc.Command{Args: []string{"rsh", "-c", containerName, podName, "rm", "-f", pushMarkerPath}})
+}
+
+func hasIncompletePush(podName string, containerName string) bool {
+	out, err := oc.ExecCommandAndReturn(oc.Command{Args: []string{"rsh", "-c", containerName, podName, "sh", "-c", "test -f " + pushMarkerPath + " && echo present"}})
+	return err == nil && strings.TrimSpace(out) == "present"
+}
+
+// deleteExtraRemoteFiles removes files under remoteDir, inside podName, that
+// have no corresponding file under localDir, so `sd push --delete-extra`
+// makes the pod a true mirror instead of only ever adding/updating files.
+func deleteExtraRemoteFiles(podName string, containerName string, localDir string, remoteDir string, ignorePatterns []string) {
+	localFiles, err := buildpack.LocalFileSet(localDir, ignorePatterns)
+	if err != nil {
+		log.Warnf("--delete-extra: unable to walk '%s': %s", localDir, err.Error())
+		return
+	}
+
+	listing, err := oc.ExecCommandAndReturn(oc.Command{Args: []string{"rsh", "-c", containerName, podName, "find", remoteDir, "-type", "f"}})
+	if err != nil {
+		log.Warnf("--delete-extra: unable to list pod-side files under '%s': %s", remoteDir, err.Error())
+		return
+	}
+
+	for _, remotePath := range buildpack.RemoteExtraFiles(listing, remoteDir, localFiles) {
+		log.Infof("--delete-extra: removing stale pod-side file '%s'", remotePath)
+		oc.ExecCommand(oc.Command{Args: []string{"rsh", "-c", containerName, podName, "rm", "-f", remotePath}})
+	}
+}
+
+// pushConcurrently copies localPath to remotePath the same way
+// buildpack.CopyToPod does (localPath's own base name becomes a subdirectory
+// of remotePath, matching `oc cp`), but when localPath is a directory, its
+// immediate children are pushed as separate tar streams across up to
+// concurrency goroutines instead of one big recursive copy. Each child still
+// carries its own subtree recursively, so nested source directories land
+// intact. It returns the total bytes copied across every stream.
+func pushConcurrently(setup config.Tool, pod *v1.Pod, containerName string, localPath string, remotePath string, concurrency int, limiter *rate.Limiter) (int64, error) {
+	info, err := os.Stat(localPath)
+	if err != nil {
+		return 0, err
+	}
+	if !info.IsDir() {
+		return buildpack.CopyToPod(setup.Clientset, setup.RestConfig, pod, localPath, remotePath, limiter)
+	}
+
+	entries, err := ioutil.ReadDir(localPath)
+	if err != nil {
+		return 0, err
+	}
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	// A whole-directory CopyToPod carries localPath's own base name into the
+	// tar stream, so splitting it into per-child streams needs that same
+	// directory created remotely up front, since each child's stream only
+	// knows about itself.
+	targetDir := filepath.ToSlash(filepath.Join(remotePath, filepath.Base(localPath)))
+	oc.ExecCommand(oc.Command{Args: []string{"rsh", "-c", containerName, pod.Name, "mkdir", "-p", targetDir}})
+
+	jobs := make(chan string)
+	type result struct {
+		n   int64
+		err error
+	}
+	results := make(chan result)
+
+	var wg sync.WaitGroup
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for childPath := range jobs {
+				n, err := buildpack.CopyToPod(setup.Clientset, setup.RestConfig, pod, childPath, targetDir, limiter)
+				results <- result{n: n, err: err}
+			}
+		}()
+	}
+
+	go func() {
+		for _, entry := range entries {
+			jobs <- filepath.Join(localPath, entry.Name())
+		}
+		close(jobs)
+	}()
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	var total int64
+	var firstErr error
+	for r := range results {
+		total += r.n
+		if r.err != nil && firstErr == nil {
+			firstErr = r.err
+		}
+	}
+	return total, firstErr
+}
+
+// warnLargeFiles checks path (a file or directory) for entries over
+// maxBytes and, unless force is set, aborts the push listing them --
+// accidentally syncing a multi-GB build artifact or local database into the
+// dev pod is a common footgun .sdignore alone doesn't catch. maxBytes <= 0
+// disables the check.
+func warnLargeFiles(path string, ignorePatterns []string, maxBytes int64, force bool) {
+	if maxBytes <= 0 {
+		return
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return
+	}
+
+	var large []string
+	if info.IsDir() {
+		large, err = buildpack.LargeFiles(path, ignorePatterns, maxBytes)
+		if err != nil {
+			log.Warnf("--max-file-size: unable to walk '%s': %s", path, err.Error())
+			return
+		}
+	} else if info.Size() > maxBytes {
+		large = []string{filepath.Base(path)}
+	}
+
+	if len(large) == 0 {
+		return
+	}
+
+	for _, f := range large {
+		log.Warnf("--max-file-size: '%s' under '%s' exceeds %d bytes", f, path, maxBytes)
+	}
+	if !force {
+		log.Fatalf("Refusing to push %d file(s) over --max-file-size (%d bytes); use --force to push anyway or add them to .sdignore", len(large), maxBytes)
+	}
+}
+
+// pushedFileSet collects the slash-separated, artefact-relative paths of
+// every local file `sd push` is about to copy, for --auto-restart's
+// classification -- the extension is all NeedsRestart looks at, so the
+// exact directory it came from doesn't matter.
+func pushedFileSet(pwd string, artefacts []string, ignorePatterns []string) map[string]bool {
+	files := map[string]bool{}
+
+	for _, artefact := range artefacts {
+		path := pwd + "/" + artefact
+		info, err := os.Stat(path)
+		if err != nil {
+			continue
+		}
+		if !info.IsDir() {
+			files[artefact] = true
+			continue
+		}
+
+		found, err := buildpack.LocalFileSet(path, ignorePatterns)
+		if err != nil {
+			log.Warnf("--auto-restart: unable to walk '%s': %s", path, err.Error())
+			continue
+		}
+		for relPath := range found {
+			files[relPath] = true
+		}
+	}
+
+	return files
+}
+
+// restartIfNeeded restarts the application under supervisord when
+// autoRestart is set and pushedFiles contains a file that actually requires
+// one (buildpack.NeedsRestart), so repeated pushes of static assets don't
+// pay for a restart Spring DevTools' own hot-reload already covers.
+func restartIfNeeded(setup config.Tool, podName string, containerName string, autoRestart bool, pushedFiles map[string]bool) {
+	if !autoRestart {
+		return
+	}
+
+	extensions := setup.Application.RestartExtensions
+	if len(extensions) == 0 {
+		extensions = buildpack.DefaultRestartExtensions
+	}
+
+	if !buildpack.NeedsRestart(pushedFiles, extensions) {
+		log.Info("--auto-restart: no pushed file requires a restart, leaving the application running")
+		return
+	}
+
+	_, runCmd := config.CommandNames(setup.Application.BuildTool)
+	log.Info("--auto-restart: restarting the application")
+	restartApplication(podName, containerName, runCmd)
+}
+
 func init() {
 	var (
-		mode      string
-		artefacts = []string{"src", "pom.xml"}
-		modes     = []string{"source", "binary"}
+		mode             string
+		syncBackend      string
+		followSymlinks   bool
+		deleteExtra      bool
+		syncConcurrency  int
+		throttleBytesSec int64
+		maxFileSize      int64
+		force            bool
+		autoRestart      bool
+		artefacts        = []string{"src", "pom.xml"}
+		modes            = []string{"source", "binary"}
+		syncBackends     = []string{"native", "oc"}
 	)
 
 	pushCmd := &cobra.Command{
@@ -37,28 +259,128 @@ func init() {
 				log.WithField("mode", mode).Fatal("The provided mode is not supported: ")
 			}
 
-			log.Infof("Push command called with mode '%s'", mode)
+			var validBackend bool
+			for _, value := range syncBackends {
+				if syncBackend == value {
+					validBackend = true
+				}
+			}
+			if !validBackend {
+				log.WithField("sync-backend", syncBackend).Fatal("The provided sync backend is not supported: ")
+			}
+
+			log.Infof("Push command called with mode '%s', sync backend '%s'", mode, syncBackend)
 
 			setup, pod := SetupAndWaitForPod()
+			if !setup.Application.MountSource {
+				log.Fatal("'sd push' requires mountSource; this dev pod runs its image as-is and has no source-sync wiring to push into")
+			}
 			podName := pod.Name
 			containerName := setup.Application.Name
 
+			if hasIncompletePush(podName, containerName) {
+				log.Warn("A previous push was interrupted before it finished; the pod's source may be inconsistent until this push completes")
+			}
+
 			log.Info("Copy files from the local developer project to the pod")
+			markPushStart(podName, containerName)
 
 			switch mode {
 			case "source":
+				ignorePatterns := buildpack.LoadIgnorePatterns(oc.Client.Pwd)
+
+				for i := range artefacts {
+					warnLargeFiles(oc.Client.Pwd+"/"+artefacts[i], ignorePatterns, maxFileSize, force)
+				}
+
+				var pushedFiles map[string]bool
+				if autoRestart {
+					pushedFiles = pushedFileSet(oc.Client.Pwd, artefacts, ignorePatterns)
+				}
+
+				if syncBackend == "oc" {
+					if followSymlinks || throttleBytesSec > 0 || cmd.Flags().Changed("sync-concurrency") {
+						log.Warn("--follow-symlinks, --throttle and --sync-concurrency have no effect with --sync-backend oc, which delegates to 'oc rsync'")
+					}
+
+					for i := range artefacts {
+						log.Debug("Artefact : ", artefacts[i])
+						artefactPath := oc.Client.Pwd + "/" + artefacts[i]
+						if _, err := os.Stat(artefactPath); os.IsNotExist(err) {
+							continue
+						}
+
+						args := []string{"rsync", "--no-perms", artefactPath, podName + ":/tmp/src/", "-c", containerName}
+						if _, err := oc.ExecCommandAndReturn(oc.Command{Args: args}); err != nil {
+							log.Fatalf("Unable to rsync '%s': %s", artefactPath, err.Error())
+						}
+
+						if deleteExtra {
+							if info, err := os.Stat(artefactPath); err == nil && info.IsDir() {
+								deleteExtraRemoteFiles(podName, containerName, artefactPath, "/tmp/src/"+artefacts[i], ignorePatterns)
+							}
+						}
+					}
+					markPushComplete(podName, containerName)
+					log.Info("Pushed via 'oc rsync'")
+					restartIfNeeded(setup, podName, containerName, autoRestart, pushedFiles)
+					break
+				}
+
+				var limiter *rate.Limiter
+				if throttleBytesSec > 0 {
+					limiter = rate.NewLimiter(rate.Limit(throttleBytesSec), int(throttleBytesSec))
+				}
+
+				start := time.Now()
+				var totalBytes int64
+
 				for i := range artefacts {
 					log.Debug("Artefact : ", artefacts[i])
-					args := []string{"cp", oc.Client.Pwd + "/" + artefacts[i], podName + ":/tmp/src/", "-c", containerName}
-					log.Infof("Copy cmd : %s", args)
-					oc.ExecCommand(oc.Command{Args: args})
+					artefactPath := oc.Client.Pwd + "/" + artefacts[i]
+
+					copySrc, cleanup, err := buildpack.ResolveSymlinksForPush(artefactPath, followSymlinks)
+					if err != nil {
+						log.Fatal(err)
+					}
+
+					n, err := pushConcurrently(setup, pod, containerName, copySrc, "/tmp/src/", syncConcurrency, limiter)
+					if err != nil {
+						log.Fatalf("Unable to push '%s': %s", artefactPath, err.Error())
+					}
+					totalBytes += n
+					cleanup()
+
+					if deleteExtra {
+						if info, err := os.Stat(artefactPath); err == nil && info.IsDir() {
+							deleteExtraRemoteFiles(podName, containerName, artefactPath, "/tmp/src/"+artefacts[i], ignorePatterns)
+						}
+					}
 				}
+				markPushComplete(podName, containerName)
+
+				elapsed := time.Since(start)
+				throughput := float64(0)
+				if elapsed.Seconds() > 0 {
+					throughput = float64(totalBytes) / elapsed.Seconds()
+				}
+				log.Infof("Pushed %d bytes in %s (%.0f bytes/sec effective)", totalBytes, elapsed.Round(time.Millisecond), throughput)
+				restartIfNeeded(setup, podName, containerName, autoRestart, pushedFiles)
 			case "binary":
-				targetDir := oc.Client.Pwd + "/target/"
+				if deleteExtra {
+					log.Warn("--delete-extra has no effect in binary mode, which pushes a single archive")
+				}
+				buildDir := "target"
+				buildCmdHint := "mvn clean package"
+				if setup.Application.BuildTool == types.BuildToolGradle {
+					buildDir = "build/libs"
+					buildCmdHint = "gradle build"
+				}
+				targetDir := oc.Client.Pwd + "/" + buildDir + "/"
 				if _, err := os.Stat(targetDir); os.IsNotExist(err) {
-					log.Error("No output found! Please build the application with 'mvn clean package' before pushing")
+					log.Errorf("No output found! Please build the application with '%s' before pushing", buildCmdHint)
 				} else {
-					filesInTarget, err := ioutil.ReadDir(oc.Client.Pwd + "/target/")
+					filesInTarget, err := ioutil.ReadDir(targetDir)
 					if err != nil {
 						panic(err)
 					}
@@ -78,11 +400,27 @@ func init() {
 					}
 
 					if archiveFile != "" {
-						args := []string{"cp", archiveFile, podName + destinationFile, "-c", containerName}
-						log.Infof("Copy cmd : %s", args)
-						oc.ExecCommand(oc.Command{Args: args})
+						warnLargeFiles(archiveFile, nil, maxFileSize, force)
+
+						if syncBackend == "oc" {
+							args := []string{"cp", archiveFile, podName + destinationFile, "-c", containerName}
+							log.Infof("Copy cmd : %s", args)
+							oc.ExecCommand(oc.Command{Args: args})
+						} else {
+							destName := strings.TrimPrefix(destinationFile, ":/deployments/")
+							stagedPath, cleanup, err := buildpack.StageRenamed(archiveFile, destName)
+							if err != nil {
+								log.Fatalf("Unable to stage '%s' for push: %s", archiveFile, err.Error())
+							}
+							if _, err := buildpack.CopyToPod(setup.Clientset, setup.RestConfig, pod, stagedPath, "/deployments", nil); err != nil {
+								cleanup()
+								log.Fatalf("Unable to push '%s': %s", archiveFile, err.Error())
+							}
+							cleanup()
+						}
+						markPushComplete(podName, containerName)
 					} else {
-						log.Error("No uber-jar file found! Please build the application with 'mvn clean package' before pushing")
+						log.Errorf("No uber-jar file found! Please build the application with '%s' before pushing", buildCmdHint)
 					}
 
 				}
@@ -93,6 +431,22 @@ func init() {
 
 	pushCmd.Flags().StringVarP(&mode, "mode", "", "source",
 		fmt.Sprintf("Mode used to push the code to the development pod. Supported modes are '%s'", strings.Join(modes, ",")))
+	pushCmd.Flags().StringVar(&syncBackend, "sync-backend", "native",
+		fmt.Sprintf("Transport used to copy files to the pod. 'native' streams a tar over the exec/SPDY connection; 'oc' shells out to 'oc rsync'/'oc cp'. Supported backends are '%s'", strings.Join(syncBackends, ",")))
+	pushCmd.Flags().BoolVar(&followSymlinks, "follow-symlinks", false,
+		"Follow symlinks in the project directory and sync their targets' contents instead of preserving them as links")
+	pushCmd.Flags().BoolVar(&deleteExtra, "delete-extra", false,
+		"Delete pod-side files absent from the local tree (respecting .sdignore), so push produces a true mirror")
+	pushCmd.Flags().IntVar(&syncConcurrency, "sync-concurrency", 4,
+		"Number of top-level source directories to push to the pod in parallel")
+	pushCmd.Flags().Int64Var(&throttleBytesSec, "throttle", 0,
+		"Limit push bandwidth to this many bytes/sec (0 = unlimited)")
+	pushCmd.Flags().Int64Var(&maxFileSize, "max-file-size", 50*1024*1024,
+		"Abort the push if a single file exceeds this many bytes, listing the offending file(s) (0 = unlimited). Pairs with .sdignore and --force")
+	pushCmd.Flags().BoolVar(&force, "force", false,
+		"Push anyway when a file exceeds --max-file-size")
+	pushCmd.Flags().BoolVar(&autoRestart, "auto-restart", false,
+		"After a source push, restart the application only if a pushed file requires it (.java, build/config files by default -- see the MANIFEST's restartExtensions). Files Spring DevTools can hot-reload on its own never trigger a restart")
 	pushCmd.MarkFlagRequired("mode")
 	pushCmd.Annotations = map[string]string{"command": "push"}
 
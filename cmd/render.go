@@ -0,0 +1,36 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	"github.com/snowdrop/spring-boot-cloud-devex/pkg/buildpack"
+)
+
+func init() {
+	renderCmd := &cobra.Command{
+		Use:     "render " + strings.Join(buildpack.RenderKinds, "|"),
+		Short:   "Render a single resource's YAML from the MANIFEST",
+		Long:    `Render the YAML for one kind of resource that "sd init" would create, resolved purely from the MANIFEST and any flag overrides. Makes no cluster calls, so it works without a kubeconfig.`,
+		Example: ` sd render deploymentconfig`,
+		Args:    cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+
+			log.Info("Render command called")
+
+			application := parseManifest()
+
+			rendered, err := buildpack.Render(args[0], application)
+			if err != nil {
+				log.Fatal(err.Error())
+			}
+			fmt.Print(rendered)
+		},
+	}
+
+	renderCmd.Annotations = map[string]string{"command": "render"}
+	rootCmd.AddCommand(renderCmd)
+}
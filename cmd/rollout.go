@@ -0,0 +1,50 @@
+package cmd
+
+import (
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	"github.com/snowdrop/spring-boot-cloud-devex/pkg/buildpack"
+)
+
+func init() {
+	pauseCmd := &cobra.Command{
+		Use:     "pause [flags]",
+		Short:   "Pause the DeploymentConfig's rollouts",
+		Long:    `Pause the DeploymentConfig's rollouts, so it doesn't redeploy on every config or image change while you make several edits. "sd env"/"sd push" still update the DC; OpenShift queues those changes and rolls them out together once resumed.`,
+		Example: "  sd rollout pause",
+		Args:    cobra.RangeArgs(0, 1),
+		Run: func(cmd *cobra.Command, args []string) {
+			setup := Setup()
+			if err := buildpack.PauseRollout(setup.RestConfig, setup.Application); err != nil {
+				log.Fatalf("Unable to pause rollout: %s", err.Error())
+			}
+		},
+	}
+
+	resumeCmd := &cobra.Command{
+		Use:     "resume [flags]",
+		Short:   "Resume the DeploymentConfig's rollouts",
+		Long:    `Resume a DeploymentConfig paused with "sd rollout pause", rolling out whatever config/image changes queued up while it was paused.`,
+		Example: "  sd rollout resume",
+		Args:    cobra.RangeArgs(0, 1),
+		Run: func(cmd *cobra.Command, args []string) {
+			setup := Setup()
+			if err := buildpack.ResumeRollout(setup.RestConfig, setup.Application); err != nil {
+				log.Fatalf("Unable to resume rollout: %s", err.Error())
+			}
+		},
+	}
+
+	rolloutCmd := &cobra.Command{
+		Use:     "rollout [command]",
+		Short:   "Pause or resume the DeploymentConfig's rollouts",
+		Long:    `Pause or resume the DeploymentConfig's rollouts.`,
+		Example: "  sd rollout pause\n  sd rollout resume",
+	}
+	rolloutCmd.AddCommand(pauseCmd)
+	rolloutCmd.AddCommand(resumeCmd)
+	rolloutCmd.Annotations = map[string]string{"command": "rollout"}
+
+	rootCmd.AddCommand(rolloutCmd)
+}
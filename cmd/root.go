@@ -3,6 +3,7 @@ package cmd
 import (
 	"fmt"
 	"github.com/manifoldco/promptui"
+	"github.com/mattn/go-isatty"
 	appsv1 "github.com/openshift/api/apps/v1"
 	"github.com/pkg/errors"
 	log "github.com/sirupsen/logrus"
@@ -12,13 +13,165 @@ import (
 	"github.com/snowdrop/spring-boot-cloud-devex/pkg/common/oc"
 	"github.com/spf13/cobra"
 	"k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
 	restclient "k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
 	"os"
 	"path"
+	"strings"
+	"time"
 )
 
+// exitCodeTimeout is returned when --timeout elapses, distinct from the
+// generic exit code 1 so CI can tell "timed out" apart from "failed".
+const exitCodeTimeout = 124
+
+// reusePod trusts the cached state written to .sd/config.json by a previous
+// Setup() and skips straight to the pod operation, falling back to full
+// setup if the cached pod is gone.
+var reusePod bool
+
+// manifestPathOverrides points parseManifest at one or more MANIFESTs
+// outside cwd, for projects whose source lives elsewhere or is generated.
+// `--manifest` is repeatable: the first value replaces the default cwd
+// MANIFEST, and every value after it is deep-merged on top, in order, by
+// buildpack.ParseManifest -- a base, an env overlay, and a local developer
+// overlay layered on the command line instead of hand-templated.
+var manifestPathOverrides []string
+
+// legacyOcCalls makes the existence/label-lookup calls that normally go
+// straight to the typed client instead shell out to oc, as an escape hatch
+// for clusters/auth setups where the oc CLI can reach the API server but a
+// local rest.Config can't (e.g. an oc login session using an auth plugin
+// client-go doesn't support).
+var legacyOcCalls bool
+
+// namespaceFromContext makes resolveNamespace prefer the kubeconfig's
+// current context's namespace over `oc project -q`, so namespace
+// resolution doesn't depend on the `oc` binary at all unless the
+// kubeconfig doesn't declare one.
+var namespaceFromContext bool
+
+// namespaceOverride is an explicit --namespace, which always wins over the
+// MANIFEST's namespace field, `oc project -q`, and the kubeconfig's
+// current context; see resolveNamespace/config.ResolveNamespace.
+var namespaceOverride string
+
+// namespaceSuffix, when set, is appended to the resolved namespace as
+// "<namespace>-<namespaceSuffix>" and Setup() creates it if it doesn't
+// already exist, for ephemeral per-PR/per-branch preview deployments that
+// don't require a namespace to have been provisioned ahead of time.
+var namespaceSuffix string
+
+// ephemeral is shorthand for a --namespace-suffix derived from the CI's own
+// build id (see config.ResolveEphemeralSuffix), so a pipeline doesn't have
+// to compute and thread one through itself. Ignored when namespaceSuffix
+// is set explicitly.
+var ephemeral bool
+
+// manifestProfile selects one of the MANIFEST's `profiles:` entries, merged
+// over the base fields by buildpack.ParseManifest -- e.g. `--manifest-profile
+// prod` to pick the prod namespace/replicas/env from a single committed
+// MANIFEST instead of templating several files. Named "manifest-profile"
+// rather than "profile" since that flag is already taken by the Go CPU
+// profiler (see cmd/profile.go).
+var manifestProfile string
+
+// impersonateUser and impersonateGroups mirror `kubectl --as`/`--as-group`,
+// making every API call run as the impersonated identity instead of the
+// caller's own user. Useful for admins reproducing a developer's RBAC
+// permission issues.
+var impersonateUser string
+var impersonateGroups []string
+
+// insecureSkipTLSVerify disables TLS certificate verification against the
+// cluster's API server, for local dev clusters with self-signed certs.
+// Never use this against a production cluster: it makes every API call
+// vulnerable to a man-in-the-middle.
+var insecureSkipTLSVerify bool
+
+// timeout bounds the whole command; see startTimeoutWatchdog.
+var timeout time.Duration
+
+// waitFor is the condition SetupAndWaitForPod blocks on: "running" (the
+// historical default), "ready" (its readiness probe has passed), or
+// "healthy" (actuator/health responds right now). Shared by every command
+// that waits for the dev pod, e.g. `sd run --wait-for ready`, `sd compile`.
+var waitFor string
+
+// healthyConsecutiveSuccesses is how many actuator/health checks in a row
+// must pass before `--wait-for healthy` declares the pod healthy, so a
+// single lucky response right after a restart doesn't short-circuit the
+// wait. The flag defaults to 1, the historical first-success-wins
+// behavior; <= 0 behaves like 1.
+var healthyConsecutiveSuccesses int
+
+// healthyMaxFailures caps the total actuator/health check failures
+// `--wait-for healthy` tolerates before giving up early instead of
+// running out the clock on --timeout. 0 (the flag's default) means no
+// cap -- only the overall timeout can fail the wait, the historical
+// behavior.
+var healthyMaxFailures int
+
+// healthCheckBudget builds the HealthCheckBudget WaitAndGetPod applies to
+// `--wait-for healthy`, from --healthy-consecutive-successes/
+// --healthy-max-failures.
+func healthCheckBudget() buildpack.HealthCheckBudget {
+	return buildpack.HealthCheckBudget{
+		ConsecutiveSuccesses: healthyConsecutiveSuccesses,
+		MaxFailures:          healthyMaxFailures,
+	}
+}
+
+// selector overrides the built-in io.openshift.odo=inject-supervisord label
+// used to discover the dev pod's resources, so sd can manage workloads
+// created by other tools. Format: "key=value".
+var selector string
+
+// confirmDestructive asks the user to confirm a destructive action with a
+// y/N prompt, shared by every command that can wipe resources (clean,
+// --recreate, ...) so they all behave the same way. skip bypasses the
+// prompt outright (--yes/--force). When stdin isn't a terminal -- e.g. a CI
+// job -- there's no one to answer a prompt, so it aborts instead of hanging
+// unless skip is set.
+func confirmDestructive(action string, skip bool) bool {
+	if skip {
+		return true
+	}
+
+	if !isatty.IsTerminal(os.Stdin.Fd()) {
+		log.Fatalf("%s is destructive and stdin isn't a terminal; rerun with --yes to confirm non-interactively", action)
+	}
+
+	prompt := promptui.Prompt{
+		Label:     fmt.Sprintf("%s. Continue", action),
+		IsConfirm: true,
+	}
+
+	if _, err := prompt.Run(); err != nil {
+		log.Info("Aborted")
+		return false
+	}
+
+	return true
+}
+
+// discoveryLabel splits --selector into its name/value pair, falling back
+// to the built-in odo label when the flag wasn't set.
+func discoveryLabel() (string, string) {
+	if selector == "" {
+		return buildpack.OdoLabelName, buildpack.OdoLabelValue
+	}
+
+	parts := strings.SplitN(selector, "=", 2)
+	if len(parts) != 2 {
+		log.Fatalf("--selector must be in the form key=value, got '%s'", selector)
+	}
+	return parts[0], parts[1]
+}
+
 // rootCmd represents the base command when called without any subcommands
 var rootCmd = &cobra.Command{
 	Use:   "sd",
@@ -36,6 +189,83 @@ var rootCmd = &cobra.Command{
 func init() {
 	rootCmd.PersistentFlags().StringP("kubeconfig", "k", "", "Path to a kubeconfig ($HOME/.kube/config). Only required if out-of-cluster.")
 	rootCmd.PersistentFlags().StringP("masterurl", "", "", "The address of the Kubernetes API server. Overrides any value in kubeconfig. Only required if out-of-cluster.")
+	rootCmd.PersistentFlags().Bool("no-color", false, "Disable colorized output")
+	rootCmd.PersistentFlags().BoolP("quiet", "q", false, "Only print warnings and errors")
+	rootCmd.PersistentFlags().BoolVar(&reusePod, "reuse-pod", false, "Trust the cached dev pod from .sd/config.json and skip re-running setup")
+	rootCmd.PersistentFlags().BoolVar(&namespaceFromContext, "namespace-from-context", false, "Resolve the namespace from the kubeconfig's current context instead of 'oc project -q'")
+	rootCmd.PersistentFlags().BoolVar(&legacyOcCalls, "legacy-oc-calls", false, "Shell out to the oc CLI for existence/label-lookup checks instead of the typed client, for environments where oc works but a direct API connection doesn't")
+	rootCmd.PersistentFlags().StringArrayVar(&manifestPathOverrides, "manifest", []string{}, "Path to the MANIFEST file. Repeatable (--manifest a.yaml --manifest b.yaml): the first file replaces the default 'MANIFEST' in the current directory, and every file after it is deep-merged on top, in order -- nested maps merge key-by-key, everything else (scalars, lists) is replaced outright. Every path given must exist and be readable")
+	rootCmd.PersistentFlags().StringVar(&namespaceOverride, "namespace", "", "Namespace to operate in. Overrides the MANIFEST's namespace field, 'oc project -q', and the kubeconfig's current context")
+	rootCmd.PersistentFlags().StringVar(&namespaceSuffix, "namespace-suffix", "", "Append '-<suffix>' to the resolved namespace, creating it if it doesn't already exist. For ephemeral per-PR/per-branch preview deployments -- pass the same suffix again to 'sd clean' to tear one down")
+	rootCmd.PersistentFlags().BoolVar(&ephemeral, "ephemeral", false, "Shorthand for --namespace-suffix derived from the CI's own build id (GITHUB_RUN_ID, BUILD_NUMBER, CI_JOB_ID, CI_PIPELINE_ID), falling back to a random one when none is set. Ignored if --namespace-suffix is also given")
+	rootCmd.PersistentFlags().StringVar(&manifestProfile, "manifest-profile", "", "Select a profile declared under the MANIFEST's 'profiles:' map, merged over its base fields (namespace, replicas, resources, env, ...). Errors if the named profile isn't declared")
+	rootCmd.PersistentFlags().StringVar(&waitFor, "wait-for", string(buildpack.WaitForRunning), "Condition to wait for before using the dev pod: running, ready, or healthy")
+	rootCmd.PersistentFlags().IntVar(&healthyConsecutiveSuccesses, "healthy-consecutive-successes", 1, "With --wait-for healthy, require this many actuator/health checks in a row to pass before declaring the pod healthy")
+	rootCmd.PersistentFlags().IntVar(&healthyMaxFailures, "healthy-max-failures", 0, "With --wait-for healthy, give up early after this many total failed health checks instead of waiting out --timeout. 0 means no cap")
+	rootCmd.PersistentFlags().StringVar(&selector, "selector", "", "Override the label (key=value) used to discover the dev pod's resources, for managing workloads created by other tools. Defaults to io.openshift.odo=inject-supervisord")
+	rootCmd.PersistentFlags().StringVar(&impersonateUser, "as", "", "Username to impersonate for every API call")
+	rootCmd.PersistentFlags().StringArrayVar(&impersonateGroups, "as-group", []string{}, "Group to impersonate for every API call, can be repeated")
+	rootCmd.PersistentFlags().DurationVar(&timeout, "timeout", 0, "Abort the command and exit with code 124 if it hasn't finished within this duration (e.g. 5m). Disabled by default")
+	rootCmd.PersistentFlags().BoolVar(&insecureSkipTLSVerify, "insecure-skip-tls-verify", false, "Skip API server TLS certificate verification (self-signed certs on local dev clusters). NEVER use against a production cluster")
+	rootCmd.PersistentFlags().StringVar(&profilePath, "profile", "", "Write a Go CPU profile of this command's run to FILE, for diagnosing slow commands")
+	rootCmd.PersistentFlags().StringVar(&tracePath, "trace", "", "Write a Go execution trace of this command's run to FILE, alongside --profile")
+	rootCmd.PersistentFlags().MarkHidden("profile")
+	rootCmd.PersistentFlags().MarkHidden("trace")
+
+	rootCmd.PersistentPreRun = func(cmd *cobra.Command, args []string) {
+		configureLogColors(cmd)
+		configureLogLevel(cmd)
+		if timeout > 0 {
+			startTimeoutWatchdog(timeout)
+		}
+		if profilePath != "" || tracePath != "" {
+			stopProfiling := startProfiling()
+			rootCmd.PersistentPostRun = func(cmd *cobra.Command, args []string) {
+				stopProfiling()
+			}
+		}
+	}
+}
+
+// startTimeoutWatchdog exits the process with exitCodeTimeout once duration
+// elapses, bounding the whole command for CI use. The vendored client-go
+// here predates context.Context-accepting client methods, so this can't
+// cancel an in-flight API call or `oc` exec stream individually the way a
+// request-scoped context would -- it bounds the command the same blunt way
+// the `timeout(1)` utility would wrapping the whole process.
+func startTimeoutWatchdog(duration time.Duration) {
+	time.AfterFunc(duration, func() {
+		fmt.Fprintf(os.Stderr, "sd: timed out after %s (--timeout)\n", duration)
+		os.Exit(exitCodeTimeout)
+	})
+}
+
+// configureLogLevel raises the minimum severity to Warn when --quiet is
+// passed, so Setup()'s log.Fatal calls still surface but the routine Info
+// logging doesn't.
+func configureLogLevel(cmd *cobra.Command) {
+	quiet, _ := cmd.Flags().GetBool("quiet")
+	if quiet {
+		log.SetLevel(log.WarnLevel)
+	}
+}
+
+// configureLogColors sets up logrus' TextFormatter so that output is
+// colorized when stdout is a terminal, and left plain when piped, when
+// NO_COLOR is set, or when --no-color is passed.
+func configureLogColors(cmd *cobra.Command) {
+	log.SetFormatter(&log.TextFormatter{
+		DisableColors: colorsDisabled(cmd),
+	})
+}
+
+// colorsDisabled applies the same --no-color/NO_COLOR check
+// configureLogColors uses for logrus' own formatter, for other commands
+// (e.g. `sd logs --all-containers`) that colorize raw output of their own.
+func colorsDisabled(cmd *cobra.Command) bool {
+	noColor, _ := cmd.Flags().GetBool("no-color")
+	_, noColorEnv := os.LookupEnv("NO_COLOR")
+	return noColor || noColorEnv
 }
 
 func Execute() {
@@ -60,49 +290,271 @@ func checkError(err error, context string, a ...interface{}) {
 	}
 }
 
+// applyClusterSettings fills in application's RouteDomain/Registry/
+// PVCStorageClass from ~/.sd/clusters.yaml, keyed by serverURL, wherever the
+// MANIFEST left them empty -- the MANIFEST always wins over the per-cluster
+// default.
+func applyClusterSettings(application *types.Application, serverURL string) {
+	settings := config.LoadClusterSettings(serverURL)
+
+	if application.RouteDomain == "" {
+		application.RouteDomain = settings.RouteDomain
+	}
+	if application.Registry == "" {
+		application.Registry = settings.Registry
+	}
+	if application.PVCStorageClass == "" {
+		application.PVCStorageClass = settings.StorageClass
+	}
+}
+
+// ensureNamespace creates namespace if it doesn't already exist, for the
+// namespaces --namespace-suffix/--ephemeral generate on the fly rather than
+// the ones an operator is expected to have provisioned ahead of time
+// (e.g. with `oc new-project`).
+func ensureNamespace(clientset *kubernetes.Clientset, namespace string) error {
+	if _, err := clientset.CoreV1().Namespaces().Get(namespace, metav1.GetOptions{}); err == nil {
+		return nil
+	} else if !apierrors.IsNotFound(err) {
+		return err
+	}
+
+	log.Infof("Creating namespace '%s'", namespace)
+	_, err := clientset.CoreV1().Namespaces().Create(&v1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{Name: namespace},
+	})
+	return err
+}
+
 func Setup() config.Tool {
+	return SetupFromApplication(parseManifest())
+}
+
+// SetupFromApplication runs everything Setup() does after resolving the
+// Application -- namespace/cluster/arch resolution and resource
+// provisioning -- starting from an already fully-resolved application
+// instead of parsing flags/MANIFEST for one, for `sd apply -f spec.yaml`
+// recreating a `sd init --save-spec`-captured application verbatim
+// against a (possibly different) cluster.
+func SetupFromApplication(application types.Application) config.Tool {
 	tool := &config.Tool{}
 
-	// Parse MANIFEST
-	tool.Application = parseManifest()
+	tool.Application = application
 
 	// Get K8s' config file
 	tool.KubeConfig = getK8Config(*rootCmd)
 
 	// Retrieve the current namespace
-	currentNs, err := oc.ExecCommandAndReturn(oc.Command{Args: []string{"project", "-q"}})
-	if err != nil {
-		log.Fatal(err)
-	}
-	log.Infof("Using '%s' namespace", currentNs)
-	tool.Application.Namespace = currentNs
+	tool.Application.Namespace = resolveNamespace(tool.KubeConfig, tool.Application.Namespace)
+	log.Infof("Using '%s' namespace", tool.Application.Namespace)
 
 	// Create Kube Rest's Config Client
 	tool.RestConfig = createKubeRestconfig(tool.KubeConfig)
 	tool.Clientset = createClientSet(tool.KubeConfig, tool.RestConfig)
 
+	if namespaceSuffix != "" || ephemeral {
+		if err := ensureNamespace(tool.Clientset, tool.Application.Namespace); err != nil {
+			log.Fatalf("Unable to create namespace '%s': %s", tool.Application.Namespace, err.Error())
+		}
+	}
+
+	applyClusterSettings(&tool.Application, tool.RestConfig.Host)
+
+	if tool.Application.DevImage != "" {
+		registryHost := buildpack.DiscoverInternalRegistryHost(tool.RestConfig)
+		tool.Application.DevImage = buildpack.RewriteInternalRegistryHost(tool.Application.DevImage, registryHost)
+	}
+
+	if tool.Application.Arch == "" {
+		detected, err := buildpack.DetectNodeArchitecture(tool.Clientset)
+		if err != nil {
+			log.Warnf("Unable to detect node architecture, defaulting to amd64: %s", err.Error())
+			detected = "amd64"
+		}
+		tool.Application.Arch = detected
+	}
+	log.Infof("Using '%s' architecture", tool.Application.Arch)
+
 	finishSetupAndSetApplicationName(tool)
 
 	return *tool
 }
 
 func SetupAndWaitForPod() (config.Tool, *v1.Pod) {
+	if reusePod {
+		if tool, pod, ok := tryReuseCachedPod(); ok {
+			return tool, pod
+		}
+		log.Info("Cached pod is gone, falling back to full setup")
+	}
+
 	setup := Setup()
 
+	condition, err := buildpack.ParseWaitCondition(waitFor)
+	if err != nil {
+		log.Fatal(err.Error())
+	}
+
 	// Wait till the dev pod is available
-	log.Info("Wait till the dev pod is available")
-	pod, err := buildpack.WaitAndGetPod(setup.Clientset, setup.Application)
+	log.Infof("Wait till the dev pod is %s", condition)
+	pod, err := buildpack.WaitAndGetPod(setup.Clientset, setup.Application, condition, healthCheckBudget())
 	if err != nil {
+		buildpack.DumpDiagnostics(setup.Application)
 		log.Fatalf("Pod watch error: %s", err)
 	}
 
+	if err := config.SaveState(config.State{
+		ApplicationName: setup.Application.Name,
+		Namespace:       setup.Application.Namespace,
+		PodName:         pod.Name,
+	}); err != nil {
+		log.Debugf("Unable to cache pod state: %s", err.Error())
+	}
+
 	return setup, pod
 }
 
+// tryReuseCachedPod builds a Tool from the state cached by a previous
+// SetupAndWaitForPod, without making any of the discovery calls Setup()
+// normally does. It reports ok=false if no cached pod exists anymore.
+func tryReuseCachedPod() (config.Tool, *v1.Pod, bool) {
+	state, ok := config.LoadState()
+	if !ok {
+		return config.Tool{}, nil, false
+	}
+
+	tool := config.Tool{}
+	tool.Application = parseManifest()
+	tool.Application.Name = state.ApplicationName
+	tool.Application.Namespace = state.Namespace
+	tool.KubeConfig = getK8Config(*rootCmd)
+	tool.RestConfig = createKubeRestconfig(tool.KubeConfig)
+	tool.Clientset = createClientSet(tool.KubeConfig, tool.RestConfig)
+
+	if legacyOcCalls {
+		if !oc.Exists("pod", state.PodName) {
+			return config.Tool{}, nil, false
+		}
+	} else if _, err := tool.Clientset.CoreV1().Pods(state.Namespace).Get(state.PodName, metav1.GetOptions{}); err != nil {
+		return config.Tool{}, nil, false
+	}
+
+	log.Infof("Reusing cached dev pod '%s' (--reuse-pod)", state.PodName)
+
+	pod := &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      state.PodName,
+			Namespace: state.Namespace,
+		},
+	}
+	return tool, pod, true
+}
+
+// parseManifest gathers every cmd-package flag var ApplicationFromFlags
+// accepts and delegates to it; the precedence rules themselves live there,
+// as a pure function that's unit-testable without cobra or a cluster.
 func parseManifest() types.Application {
 	log.Info("Parse MANIFEST of the project if it exists")
 	current, _ := os.Getwd()
-	return buildpack.ParseManifest(current + "/MANIFEST")
+
+	primaryOverride := ""
+	if len(manifestPathOverrides) > 0 {
+		primaryOverride = manifestPathOverrides[0]
+	}
+	manifestPath, err := buildpack.ResolveManifestPath(current, primaryOverride)
+	if err != nil {
+		log.Fatal(err.Error())
+	}
+
+	var manifestOverlays []string
+	if len(manifestPathOverrides) > 1 {
+		for _, override := range manifestPathOverrides[1:] {
+			overlayPath, err := buildpack.ResolveManifestPath(current, override)
+			if err != nil {
+				log.Fatal(err.Error())
+			}
+			manifestOverlays = append(manifestOverlays, overlayPath)
+		}
+	}
+
+	return buildpack.ApplicationFromFlags(manifestPath, buildpack.ApplicationFlags{
+		Profile:          manifestProfile,
+		DevImage:         devImage,
+		Arch:             arch,
+		MountSource:      mountSource,
+		GitURL:           gitURL,
+		GitRef:           gitRef,
+		Replicas:         replicas,
+		MavenSettings:    mavenSettings,
+		ExtraLabels:      extraLabels,
+		UsePVC:           usePVC,
+		AnnotationsFile:  annotationsFile,
+		ManifestOverlays: manifestOverlays,
+		ProbePreset:      probePreset,
+	})
+}
+
+// resolveNamespace picks the namespace to deploy into. Precedence (highest
+// first): --namespace, the MANIFEST's `namespace:` field, then `oc project
+// -q` falling back to the kubeconfig's current context if that's empty (or
+// the reverse, with --namespace-from-context). The actual precedence rules
+// live in config.ResolveNamespace, a pure function table-driven tested on
+// its own; this just gathers the raw sources.
+func resolveNamespace(kubeCfg config.Kube, manifestNamespace string) string {
+	ocNamespace, ocErr := namespaceFromOc()
+	kubeContextNamespace, kubeErr := namespaceFromKubeConfig(kubeCfg)
+
+	resolved, err := config.ResolveNamespace(config.NamespaceSources{
+		Explicit:          namespaceOverride,
+		Manifest:          manifestNamespace,
+		PreferKubeContext: namespaceFromContext,
+		OcProject:         ocNamespace,
+		OcProjectErr:      ocErr,
+		KubeContext:       kubeContextNamespace,
+		KubeContextErr:    kubeErr,
+	})
+	if err != nil {
+		log.Fatal(err.Error())
+	}
+	return applyNamespaceSuffix(resolved)
+}
+
+// applyNamespaceSuffix appends --namespace-suffix (or, with --ephemeral,
+// config.ResolveEphemeralSuffix's value) to namespace, or returns it
+// unchanged when neither flag is set.
+func applyNamespaceSuffix(namespace string) string {
+	suffix := namespaceSuffix
+	if suffix == "" && ephemeral {
+		suffix = config.ResolveEphemeralSuffix()
+	}
+	if suffix == "" {
+		return namespace
+	}
+	return namespace + "-" + suffix
+}
+
+func namespaceFromOc() (string, error) {
+	out, err := oc.ExecCommandAndReturn(oc.Command{Args: []string{"project", "-q"}})
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(out), nil
+}
+
+// namespaceFromKubeConfig parses the namespace of the kubeconfig's current
+// context.
+func namespaceFromKubeConfig(kubeCfg config.Kube) (string, error) {
+	rawConfig, err := clientcmd.LoadFromFile(kubeCfg.Config)
+	if err != nil {
+		return "", errors.Wrapf(err, "unable to read kubeconfig '%s'", kubeCfg.Config)
+	}
+
+	context, ok := rawConfig.Contexts[rawConfig.CurrentContext]
+	if !ok || context.Namespace == "" {
+		return "", errors.Errorf("kubeconfig '%s' has no namespace set on its current context", kubeCfg.Config)
+	}
+
+	return context.Namespace, nil
 }
 
 func getK8Config(cmd cobra.Command) config.Kube {
@@ -110,7 +562,12 @@ func getK8Config(cmd cobra.Command) config.Kube {
 	var kubeCfg = config.Kube{}
 	kubeCfgPath := cmd.Flag("kubeconfig").Value.String()
 	if kubeCfgPath == "" {
-		kubeCfg.Config = config.HomeKubePath()
+		home, err := config.HomeKubePath()
+		if err != nil {
+			log.Debugf("Unable to determine home kubeconfig path, falling back to KUBECONFIG/in-cluster config: %s", err.Error())
+		} else {
+			kubeCfg.Config = home
+		}
 	} else {
 		kubeCfg.Config = kubeCfgPath
 	}
@@ -141,19 +598,45 @@ func createKubeRestconfig(kubeCfg config.Kube) *restclient.Config {
 	if err != nil {
 		log.Fatalf("Error building kubeconfig: %s", err.Error())
 	}
+
+	if impersonateUser != "" || len(impersonateGroups) > 0 {
+		log.Infof("Impersonating user '%s' with groups %v", impersonateUser, impersonateGroups)
+		kubeRestClient.Impersonate = restclient.ImpersonationConfig{
+			UserName: impersonateUser,
+			Groups:   impersonateGroups,
+		}
+	}
+
+	if insecureSkipTLSVerify || kubeRestClient.TLSClientConfig.Insecure {
+		log.Warn("TLS certificate verification is DISABLED (--insecure-skip-tls-verify). Never use this against a production cluster: API traffic can be intercepted or tampered with")
+		kubeRestClient.TLSClientConfig.Insecure = true
+		// CAFile/CAData are meaningless once Insecure is set and rest.Config
+		// rejects having both, so drop them rather than make the caller do it.
+		kubeRestClient.TLSClientConfig.CAFile = ""
+		kubeRestClient.TLSClientConfig.CAData = nil
+	}
+
 	return kubeRestClient
 }
 
 func finishSetupAndSetApplicationName(setup *config.Tool) {
+	labelName, labelValue := discoveryLabel()
+
 	// check if we already have the DC set up, in which case use the name of the application is already set and use that
-	existingDCs, err := oc.GetNamesByLabel("dc", buildpack.OdoLabelName, buildpack.OdoLabelValue)
+	var existingDCs []string
+	var err error
+	if legacyOcCalls {
+		existingDCs, err = oc.GetNamesByLabel("dc", labelName, labelValue)
+	} else {
+		existingDCs, err = buildpack.GetNamesByLabel(setup.RestConfig, setup.Application.Namespace, labelName, labelValue)
+	}
 	if err != nil {
-		log.Fatalf("Error retrieving DeploymentConfig labeled %s=%s. Are you logged in?", buildpack.OdoLabelName, buildpack.OdoLabelValue)
+		log.Fatalf("Error retrieving DeploymentConfig labeled %s=%s. Are you logged in?", labelName, labelValue)
 	}
 	if len(existingDCs) != 0 {
 		//use the name of the first matching DeploymentConfig
 		dcName := existingDCs[0]
-		log.Infof("Using application name '%s' from the existing DeploymentConfig labeled with '%s=%s'", dcName, "io.openshift.odo", "inject-supervisord")
+		log.Infof("Using application name '%s' from the existing DeploymentConfig labeled with '%s=%s'", dcName, labelName, labelValue)
 		setup.Application.Name = dcName
 	} else {
 		// otherwise, if no DeploymentConfig exists already, we need to set the development pod up
@@ -188,22 +671,68 @@ func finishSetupAndSetApplicationName(setup *config.Tool) {
 			setup.Application.Name = applicationName
 		}
 
-		// Create ImageStreams
-		log.Info("Create ImageStreams for Supervisord and Java S2I Image of SpringBoot")
-		buildpack.CreateDefaultImageStreams(setup.RestConfig, setup.Application)
+		// Create ImageStreams, unless a prebuilt devImage was provided
+		if setup.Application.DevImage == "" {
+			log.Info("Create ImageStreams for Supervisord and Java S2I Image of SpringBoot")
+			buildpack.CreateDefaultImageStreams(setup.RestConfig, setup.Application)
+		} else {
+			log.Infof("Skipping ImageStreams: devImage '%s' is set", setup.Application.DevImage)
+		}
 
 		// Create PVC
-		log.Info("Create PVC to store m2 repo")
-		buildpack.CreatePVC(setup.Clientset, setup.Application, "1Gi")
+		if setup.Application.PVCName != "" {
+			log.Infof("Using existing PVC '%s'", setup.Application.PVCName)
+			if err := buildpack.ValidatePVC(setup.Clientset, setup.Application); err != nil {
+				log.Fatalf("Unable to use --use-pvc: %s", err.Error())
+			}
+		} else if !noCache {
+			log.Info("Create PVC to store m2 repo")
+			pvc, err := buildpack.CreatePVC(setup.Clientset, setup.Application, "1Gi")
+			if err != nil {
+				log.Fatalf("Unable to create PVC: %s", err.Error())
+			}
+			if err := buildpack.WaitForPVCBound(setup.Clientset, setup.Application.Namespace, pvc.Name, buildpack.DefaultPVCBindTimeout); err != nil {
+				log.Fatalf("PVC did not bind: %s", err.Error())
+			}
+		} else {
+			log.Info("Skipping PVC creation (--no-cache)")
+		}
+
+		if setup.Application.MavenSettings != "" {
+			log.Infof("Create ConfigMap from Maven settings '%s'", setup.Application.MavenSettings)
+			if err := buildpack.CreateMavenSettingsConfigMap(setup.Clientset, setup.Application); err != nil {
+				log.Fatalf("Unable to create maven-settings ConfigMap: %s", err.Error())
+			}
+		}
 
 		var dc *appsv1.DeploymentConfig
 		log.Info("Create or retrieve DeploymentConfig using Supervisord and Java S2I Image of SpringBoot")
-		dc = buildpack.CreateOrRetrieveDeploymentConfig(setup.RestConfig, setup.Application, "")
+		dc = buildpack.CreateOrRetrieveDeploymentConfig(setup.RestConfig, setup.Application, setup.Application.StartCommand, noCache)
 
 		log.Info("Create Service using Template")
 		buildpack.CreateServiceTemplate(setup.Clientset, dc, setup.Application)
 
 		log.Info("Create Route using Template")
-		buildpack.CreateRouteTemplate(setup.RestConfig, setup.Application)
+		routes, errRoutes := buildpack.CreateRouteTemplate(setup.RestConfig, setup.Application)
+		if errRoutes != nil {
+			log.Fatalf("Unable to create Route: %s", errRoutes.Error())
+		}
+		for _, route := range routes {
+			if routeURL, err := buildpack.RouteURL(route); err == nil {
+				log.Infof("Route '%s' available at %s", route.Name, routeURL)
+			}
+		}
+
+		if buildpack.HPAEnabled(setup.Application) {
+			log.Info("Create HorizontalPodAutoscaler")
+			if _, err := buildpack.CreateHPA(setup.RestConfig, setup.Clientset, setup.Application); err != nil {
+				log.Fatalf("Unable to create HorizontalPodAutoscaler: %s", err.Error())
+			}
+		}
+
+		if len(setup.Application.ExtraManifests) > 0 {
+			log.Info("Applying extra manifests from MANIFEST's extraManifests")
+			buildpack.ApplyExtraManifests(setup.Application.ExtraManifests)
+		}
 	}
 }
@@ -0,0 +1,51 @@
+package cmd
+
+import (
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	"github.com/snowdrop/spring-boot-cloud-devex/pkg/common/config"
+	"github.com/snowdrop/spring-boot-cloud-devex/pkg/common/oc"
+)
+
+func init() {
+	var envOverrides []string
+
+	runCmd := &cobra.Command{
+		Use:     "run [flags]",
+		Short:   "Run the application in the development pod",
+		Long:    `Run the application in the development pod via the supervisord-managed process.`,
+		Example: "  sd run\n  sd run --env SPRING_PROFILES_ACTIVE=dev",
+		Args:    cobra.RangeArgs(0, 1),
+		Run: func(cmd *cobra.Command, args []string) {
+
+			log.Info("Run command called")
+
+			setup, pod := SetupAndWaitForPod()
+			if !setup.Application.MountSource {
+				log.Fatal("'sd run' requires mountSource (it starts the application via supervisord); this dev pod runs its image as-is, so it's already running")
+			}
+			podName := pod.Name
+			_, runProgram := config.CommandNames(setup.Application.BuildTool)
+
+			// --env is passed through `env` on the rsh invocation that starts
+			// the process, so it only applies to this run; it is not written
+			// back into the DC and won't survive a pod restart.
+			cmdArgs := []string{"rsh", podName}
+			if len(envOverrides) > 0 {
+				cmdArgs = append(cmdArgs, "env")
+				cmdArgs = append(cmdArgs, envOverrides...)
+			}
+			cmdArgs = append(cmdArgs, config.SupervisordBin, config.SupervisordCtl, "start", runProgram)
+
+			log.Info("Run the Spring Boot application ...")
+			oc.ExecCommand(oc.Command{Args: cmdArgs})
+			oc.ExecCommand(oc.Command{Args: []string{"logs", podName, "-f"}})
+		},
+	}
+
+	runCmd.Flags().StringArrayVar(&envOverrides, "env", []string{}, "KEY=VALUE env var for this run only (repeatable). Does not persist across pod restarts")
+	runCmd.Annotations = map[string]string{"command": "run"}
+
+	rootCmd.AddCommand(runCmd)
+}
@@ -0,0 +1,55 @@
+package cmd
+
+import (
+	"fmt"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	"github.com/snowdrop/spring-boot-cloud-devex/pkg/buildpack"
+)
+
+func init() {
+	statusCmd := &cobra.Command{
+		Use:     "status [flags]",
+		Short:   "Report whether the application's Routes are actually serving traffic",
+		Long:    `Report, for each of the application's Routes, whether a router has admitted it and whether the Service it targets has a ready endpoint to send that traffic to -- catching the common "deployed but 503" case, where a Route is admitted before its pod has passed its readiness probe.`,
+		Example: " sd status",
+		Args:    cobra.RangeArgs(0, 1),
+		Run: func(cmd *cobra.Command, args []string) {
+
+			log.Info("Status command called")
+
+			setup := Setup()
+
+			statuses, err := buildpack.CheckRouteStatus(setup.RestConfig, setup.Clientset, setup.Application)
+			if err != nil {
+				log.Fatalf("Unable to check Route status: %s", err.Error())
+			}
+			if len(statuses) == 0 {
+				log.Fatal("No Route exists yet")
+			}
+
+			healthy := true
+			for _, status := range statuses {
+				switch {
+				case !status.Admitted:
+					healthy = false
+					fmt.Printf("FAIL %s: %s\n", status.Name, status.Message)
+				case !status.Ready:
+					healthy = false
+					fmt.Printf("WARN %s: %s\n", status.Name, status.Message)
+				default:
+					fmt.Printf("OK   %s: admitted and serving\n", status.Name)
+				}
+			}
+
+			if !healthy {
+				log.Fatal("One or more Routes are not serving traffic yet")
+			}
+		},
+	}
+
+	statusCmd.Annotations = map[string]string{"command": "status"}
+	rootCmd.AddCommand(statusCmd)
+}
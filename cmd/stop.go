@@ -0,0 +1,45 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	"github.com/snowdrop/spring-boot-cloud-devex/pkg/common/config"
+	"github.com/snowdrop/spring-boot-cloud-devex/pkg/common/oc"
+)
+
+func init() {
+	stopCmd := &cobra.Command{
+		Use:     "stop [flags]",
+		Short:   "Stop the running application in the development pod",
+		Long:    `Stop the supervisord-managed application process in the development pod, leaving the pod itself running for a fast subsequent 'sd run'. Unlike 'sd clean', this doesn't delete any resources.`,
+		Example: "  sd stop",
+		Args:    cobra.RangeArgs(0, 1),
+		Run: func(cmd *cobra.Command, args []string) {
+
+			log.Info("Stop command called")
+
+			setup, pod := SetupAndWaitForPod()
+			if !setup.Application.MountSource {
+				log.Fatal("'sd stop' requires mountSource (it stops the application via supervisord); this dev pod runs its image as-is with no supervisord wiring")
+			}
+			podName := pod.Name
+			_, runProgram := config.CommandNames(setup.Application.BuildTool)
+
+			status, err := oc.ExecCommandAndReturn(oc.Command{Args: []string{"rsh", podName, config.SupervisordBin, config.SupervisordCtl, "status", runProgram}})
+			if err != nil {
+				log.Fatalf("Unable to determine program status: %s", err.Error())
+			}
+			log.Infof("Prior state: %s", strings.TrimSpace(status))
+
+			oc.ExecCommand(oc.Command{Args: []string{"rsh", podName, config.SupervisordBin, config.SupervisordCtl, "stop", runProgram}})
+			fmt.Println("Application stopped")
+		},
+	}
+
+	stopCmd.Annotations = map[string]string{"command": "stop"}
+	rootCmd.AddCommand(stopCmd)
+}
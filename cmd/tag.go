@@ -0,0 +1,36 @@
+package cmd
+
+import (
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/snowdrop/spring-boot-cloud-devex/pkg/buildpack"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	tagCmd := &cobra.Command{
+		Use:     "tag SOURCE_IMAGESTREAMTAG TARGET_IMAGESTREAMTAG",
+		Short:   "Retag/promote an ImageStreamTag",
+		Long:    `Retag/promote an ImageStreamTag, e.g. to promote 'dev-s2i:latest' to 'dev-s2i:stable'.`,
+		Example: ` sd tag dev-s2i:latest dev-s2i:stable`,
+		Args:    cobra.ExactArgs(2),
+		Run: func(cmd *cobra.Command, args []string) {
+
+			log.Info("Tag command called")
+
+			setup := Setup()
+
+			to := strings.SplitN(args[1], ":", 2)
+			if len(to) != 2 {
+				log.Fatalf("'%s' is not a valid NAME:TAG target", args[1])
+			}
+
+			buildpack.TagImage(setup.RestConfig, setup.Application.Namespace, args[0], to[0], to[1])
+			log.Infof("Tagged '%s' as '%s'", args[0], args[1])
+		},
+	}
+
+	tagCmd.Annotations = map[string]string{"command": "tag"}
+	rootCmd.AddCommand(tagCmd)
+}
@@ -0,0 +1,77 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	"github.com/snowdrop/spring-boot-cloud-devex/pkg/common/oc"
+)
+
+// podMetrics is the subset of metrics.k8s.io/v1beta1's PodMetrics that `sd
+// top` cares about.
+type podMetrics struct {
+	Containers []struct {
+		Name  string `json:"name"`
+		Usage struct {
+			CPU    string `json:"cpu"`
+			Memory string `json:"memory"`
+		} `json:"usage"`
+	} `json:"containers"`
+}
+
+func init() {
+	var watch bool
+
+	topCmd := &cobra.Command{
+		Use:     "top [flags]",
+		Short:   "Show the dev pod's CPU/memory usage",
+		Long:    `Show the dev pod's per-container CPU/memory usage, as reported by the cluster's metrics API (metrics.k8s.io). Requires metrics-server (or an equivalent) to be installed; fails with a clear message otherwise.`,
+		Example: "  sd top\n  sd top --watch",
+		Args:    cobra.RangeArgs(0, 1),
+		Run: func(cmd *cobra.Command, args []string) {
+
+			log.Info("Top command called")
+
+			setup, pod := SetupAndWaitForPod()
+
+			for {
+				printPodMetrics(setup.Application.Namespace, pod.Name)
+				if !watch {
+					break
+				}
+				time.Sleep(2 * time.Second)
+			}
+		},
+	}
+
+	topCmd.Flags().BoolVar(&watch, "watch", false, "Refresh every 2 seconds instead of printing once")
+	topCmd.Annotations = map[string]string{"command": "top"}
+
+	rootCmd.AddCommand(topCmd)
+}
+
+// printPodMetrics fetches and prints podName's usage via `oc get --raw`
+// against the metrics API, rather than vendoring the metrics clientset for
+// a single read-only call.
+func printPodMetrics(namespace string, podName string) {
+	out, err := oc.ExecCommandAndReturn(oc.Command{Args: []string{
+		"get", "--raw",
+		fmt.Sprintf("/apis/metrics.k8s.io/v1beta1/namespaces/%s/pods/%s", namespace, podName),
+	}})
+	if err != nil {
+		log.Fatal("Metrics API isn't available: is metrics-server installed on this cluster?")
+	}
+
+	var metrics podMetrics
+	if jsonErr := json.Unmarshal([]byte(out), &metrics); jsonErr != nil {
+		log.Fatalf("Unable to parse metrics response: %s", jsonErr.Error())
+	}
+
+	for _, container := range metrics.Containers {
+		fmt.Printf("%-20s CPU: %-10s MEMORY: %s\n", container.Name, container.Usage.CPU, container.Usage.Memory)
+	}
+}
@@ -0,0 +1,44 @@
+package cmd
+
+import (
+	"fmt"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	"github.com/snowdrop/spring-boot-cloud-devex/pkg/buildpack"
+)
+
+func init() {
+	urlCmd := &cobra.Command{
+		Use:     "url [flags]",
+		Short:   "List the application's Route URLs",
+		Long:    `List the URL (scheme + host) of every Route declared for the application.`,
+		Example: ` sd url`,
+		Args:    cobra.RangeArgs(0, 1),
+		Run: func(cmd *cobra.Command, args []string) {
+
+			setup := Setup()
+
+			routes, err := buildpack.GetRoutes(setup.RestConfig, setup.Application)
+			if err != nil {
+				log.Fatalf("Unable to retrieve Routes: %s", err.Error())
+			}
+			if len(routes) == 0 {
+				log.Fatal("No Route exists yet")
+			}
+
+			for _, route := range routes {
+				routeURL, err := buildpack.RouteURL(route)
+				if err != nil {
+					log.Warnf("%s: %s", route.Name, err.Error())
+					continue
+				}
+				fmt.Printf("%s\t%s\n", route.Name, routeURL)
+			}
+		},
+	}
+
+	urlCmd.Annotations = map[string]string{"command": "url"}
+	rootCmd.AddCommand(urlCmd)
+}
@@ -0,0 +1,57 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"k8s.io/client-go/tools/clientcmd"
+
+	"github.com/snowdrop/spring-boot-cloud-devex/pkg/common/config"
+	"github.com/snowdrop/spring-boot-cloud-devex/pkg/common/oc"
+)
+
+func init() {
+	whoamiCmd := &cobra.Command{
+		Use:     "whoami [flags]",
+		Short:   "Print the current user, cluster, context, and namespace",
+		Long:    `Print a summary of what sd would act against: the current user (from "oc whoami"), the cluster's API server URL, the kubeconfig's active context, and the resolved namespace. Run this before a destructive command to confirm you're pointed at the cluster/namespace you think you are, instead of one left over from a shared kubeconfig.`,
+		Example: ` sd whoami`,
+		Args:    cobra.RangeArgs(0, 1),
+		Run: func(cmd *cobra.Command, args []string) {
+
+			log.Info("Whoami command called")
+
+			application := parseManifest()
+			kubeCfg := getK8Config(*rootCmd)
+			restConfig := createKubeRestconfig(kubeCfg)
+			namespace := resolveNamespace(kubeCfg, application.Namespace)
+
+			user, err := oc.ExecCommandAndReturn(oc.Command{Args: []string{"whoami"}})
+			if err != nil {
+				user = fmt.Sprintf("unknown (%s)", err.Error())
+			} else {
+				user = strings.TrimSpace(user)
+			}
+
+			fmt.Printf("User:      %s\n", user)
+			fmt.Printf("Server:    %s\n", restConfig.Host)
+			fmt.Printf("Context:   %s\n", currentContext(kubeCfg))
+			fmt.Printf("Namespace: %s\n", namespace)
+		},
+	}
+
+	whoamiCmd.Annotations = map[string]string{"command": "whoami"}
+	rootCmd.AddCommand(whoamiCmd)
+}
+
+// currentContext returns the name of kubeCfg's active context, or a message
+// explaining why it couldn't be determined instead of an empty string.
+func currentContext(kubeCfg config.Kube) string {
+	rawConfig, err := clientcmd.LoadFromFile(kubeCfg.Config)
+	if err != nil {
+		return fmt.Sprintf("unknown (%s)", err.Error())
+	}
+	return rawConfig.CurrentContext
+}
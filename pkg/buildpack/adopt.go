@@ -0,0 +1,117 @@
+package buildpack
+
+import (
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	restclient "k8s.io/client-go/rest"
+	"k8s.io/client-go/util/retry"
+
+	"github.com/snowdrop/spring-boot-cloud-devex/pkg/buildpack/types"
+)
+
+// supervisordMountPath is where supervisordInitContainer shares its binary
+// with the main container; a DC without a volume mounted there wasn't built
+// by `sd` and has no supervisord for push/compile/run/exec to talk to.
+const supervisordMountPath = "/var/lib/supervisord"
+
+// AttachExisting locates a DeploymentConfig (and its Service/Routes, if
+// any) named after application that was created by hand or another tool,
+// and labels it (and them) with OdoLabelName=OdoLabelValue so
+// finishSetupAndSetApplicationName and every other sd command that
+// discovers resources by that label can find and manage them from now on.
+// It refuses to adopt a DeploymentConfig that doesn't look
+// supervisord-capable.
+func AttachExisting(config *restclient.Config, clientset kubernetes.Interface, application types.Application) error {
+	dcClient := getAppsClient(config).DeploymentConfigs(application.Namespace)
+
+	var notSupervisordCapable bool
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		dc, err := dcClient.Get(application.Name, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+
+		if !isSupervisordCapable(dc.Spec.Template.Spec.Containers) {
+			notSupervisordCapable = true
+			return nil
+		}
+
+		dc.Labels = adoptionLabels(dc.Labels)
+		_, err = dcClient.Update(dc)
+		return err
+	})
+	if apierrors.IsNotFound(err) {
+		return errors.Errorf("no DeploymentConfig named '%s' found in namespace '%s'", application.Name, application.Namespace)
+	}
+	if err != nil {
+		return errors.Wrap(err, "unable to label DeploymentConfig")
+	}
+	if notSupervisordCapable {
+		return errors.Errorf("DeploymentConfig '%s' doesn't look supervisord-capable (no container mounts %s); refusing to adopt it", application.Name, supervisordMountPath)
+	}
+	log.Infof("Attached DeploymentConfig '%s'", application.Name)
+
+	svcClient := clientset.CoreV1().Services(application.Namespace)
+	err = retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		svc, err := svcClient.Get(application.Name, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		svc.Labels = adoptionLabels(svc.Labels)
+		_, err = svcClient.Update(svc)
+		return err
+	})
+	if err == nil {
+		log.Infof("Attached Service '%s'", application.Name)
+	} else if !apierrors.IsNotFound(err) {
+		return errors.Wrap(err, "unable to label Service")
+	}
+
+	routeV1Client := getClient(config)
+	for _, route := range applicationRoutes(application) {
+		routeClient := routeV1Client.Routes(application.Namespace)
+		err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+			live, err := routeClient.Get(route.Name, metav1.GetOptions{})
+			if err != nil {
+				return err
+			}
+			live.Labels = adoptionLabels(live.Labels)
+			_, err = routeClient.Update(live)
+			return err
+		})
+		if apierrors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return errors.Wrapf(err, "unable to label Route '%s'", route.Name)
+		}
+		log.Infof("Attached Route '%s'", route.Name)
+	}
+
+	return nil
+}
+
+// isSupervisordCapable reports whether any container mounts
+// supervisordMountPath, the signal that a DC was built with the
+// supervisord init container wiring this tool depends on.
+func isSupervisordCapable(containers []corev1.Container) bool {
+	for _, container := range containers {
+		for _, mount := range container.VolumeMounts {
+			if mount.MountPath == supervisordMountPath {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// adoptionLabels returns existing overlaid with OdoLabelName=OdoLabelValue,
+// leaving every other label (and any other tool's own labels) untouched.
+func adoptionLabels(existing map[string]string) map[string]string {
+	return MergeLabels(existing, map[string]string{OdoLabelName: OdoLabelValue})
+}
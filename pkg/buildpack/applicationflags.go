@@ -0,0 +1,114 @@
+package buildpack
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	"github.com/ghodss/yaml"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/snowdrop/spring-boot-cloud-devex/pkg/buildpack/types"
+)
+
+// ApplicationFlags bundles every CLI override ApplicationFromFlags applies
+// over the MANIFEST, mirroring `sd init`'s --manifest-profile/--dev-image/
+// --arch/--mount-source/--git-url/--git-ref/--replicas/--maven-settings/
+// --label/--use-pvc flags. Zero values ("" or -1 for Replicas) mean "leave
+// the MANIFEST's value alone", the same sentinel convention the flags
+// themselves use.
+type ApplicationFlags struct {
+	Profile       string
+	DevImage      string
+	Arch          string
+	MountSource   string
+	GitURL        string
+	GitRef        string
+	Replicas      int
+	MavenSettings string
+	ExtraLabels   []string
+	UsePVC        string
+	// AnnotationsFile is the local path to a YAML/JSON file holding a flat
+	// map of pod annotations, merged over the MANIFEST's podAnnotations (the
+	// file wins on a key both declare).
+	AnnotationsFile string
+	// ManifestOverlays are additional MANIFEST paths -- `--manifest` given
+	// more than once -- deep-merged over manifestPath's content in order by
+	// ParseManifest, for layering a base, an env overlay, and a local
+	// developer overlay on the command line. Not to be confused with
+	// types.Application.ExtraManifests, the unrelated raw-k8s-YAML-to-apply
+	// feature `sd init`/`sd clean` use.
+	ManifestOverlays []string
+	// ProbePreset overrides the MANIFEST's probePreset outright, following
+	// ParseManifest's types.ProbePresetXxx/"auto" convention.
+	ProbePreset string
+}
+
+// ApplicationFromFlags resolves a types.Application purely from
+// manifestPath and flags, making no cluster calls -- unlike Setup(), which
+// additionally resolves the namespace/architecture from a live cluster and
+// provisions resources. It's for commands that only need the resolved
+// config (dry-run rendering, diffing precedence, etc.) and want to stay
+// unit-testable without a cluster.
+func ApplicationFromFlags(manifestPath string, flags ApplicationFlags) types.Application {
+	appConfig := ParseManifest(manifestPath, flags.Profile, flags.ManifestOverlays...)
+
+	if flags.DevImage != "" {
+		appConfig.DevImage = flags.DevImage
+	}
+	if flags.Arch != "" {
+		appConfig.Arch = flags.Arch
+	}
+	if flags.MountSource != "" {
+		appConfig.MountSource = flags.MountSource != "false"
+	}
+	if flags.GitURL != "" {
+		appConfig.GitURL = flags.GitURL
+	}
+	if flags.GitRef != "" {
+		appConfig.GitRef = flags.GitRef
+	}
+	if flags.Replicas >= 0 {
+		appConfig.Replicas = flags.Replicas
+	}
+	if flags.MavenSettings != "" {
+		appConfig.MavenSettings = flags.MavenSettings
+	}
+	if flags.UsePVC != "" {
+		appConfig.PVCName = flags.UsePVC
+	}
+	if flags.ProbePreset != "" {
+		appConfig.ProbePreset = ResolveProbePreset(filepath.Dir(manifestPath), flags.ProbePreset)
+	}
+
+	if len(flags.ExtraLabels) > 0 {
+		parsed := map[string]string{}
+		for _, label := range flags.ExtraLabels {
+			parts := strings.SplitN(label, "=", 2)
+			if len(parts) != 2 || parts[0] == "" {
+				log.Fatalf("--label: '%s' is not in key=value form", label)
+			}
+			if parts[0] == OdoLabelName {
+				log.Fatalf("--label: '%s' is reserved for pod discovery and cannot be overridden", OdoLabelName)
+			}
+			parsed[parts[0]] = parts[1]
+		}
+		appConfig.Labels = MergeLabels(appConfig.Labels, parsed)
+	}
+
+	if flags.AnnotationsFile != "" {
+		data, err := ioutil.ReadFile(flags.AnnotationsFile)
+		if err != nil {
+			log.Fatalf("--annotations-from-file: unable to read '%s': %s", flags.AnnotationsFile, err.Error())
+		}
+
+		var fromFile map[string]string
+		if err := yaml.Unmarshal(data, &fromFile); err != nil {
+			log.Fatalf("--annotations-from-file: unable to parse '%s': %s", flags.AnnotationsFile, err.Error())
+		}
+
+		appConfig.PodAnnotations = MergeLabels(appConfig.PodAnnotations, fromFile)
+	}
+
+	return appConfig
+}
@@ -0,0 +1,159 @@
+package buildpack_test
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/snowdrop/spring-boot-cloud-devex/pkg/buildpack"
+)
+
+func TestApplicationFromFlagsOverridesManifest(t *testing.T) {
+	dir, err := ioutil.TempDir("", "application-from-flags-test-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	manifestPath := writeManifest(t, dir, "MANIFEST", ""+
+		"name: my-app\n"+
+		"arch: amd64\n"+
+		"mountSource: true\n"+
+		"replicas: 1\n")
+
+	app := buildpack.ApplicationFromFlags(manifestPath, buildpack.ApplicationFlags{
+		DevImage:    "quay.io/example/dev:latest",
+		Arch:        "arm64",
+		MountSource: "false",
+		Replicas:    3,
+	})
+
+	if app.DevImage != "quay.io/example/dev:latest" {
+		t.Errorf("DevImage = %q, want the flag override", app.DevImage)
+	}
+	if app.Arch != "arm64" {
+		t.Errorf("Arch = %q, want the flag override %q", app.Arch, "arm64")
+	}
+	if app.MountSource {
+		t.Error("MountSource = true, want false (flag override)")
+	}
+	if app.Replicas != 3 {
+		t.Errorf("Replica = %d, want the flag override %d", app.Replicas, 3)
+	}
+}
+
+func TestApplicationFromFlagsUsePVC(t *testing.T) {
+	dir, err := ioutil.TempDir("", "application-from-flags-test-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	manifestPath := writeManifest(t, dir, "MANIFEST", "name: my-app\n")
+
+	app := buildpack.ApplicationFromFlags(manifestPath, buildpack.ApplicationFlags{
+		Replicas: -1,
+		UsePVC:   "shared-m2-cache",
+	})
+
+	if app.PVCName != "shared-m2-cache" {
+		t.Errorf("PVCName = %q, want the flag override %q", app.PVCName, "shared-m2-cache")
+	}
+}
+
+func TestApplicationFromFlagsProbePreset(t *testing.T) {
+	dir, err := ioutil.TempDir("", "application-from-flags-test-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	manifestPath := writeManifest(t, dir, "MANIFEST", ""+
+		"name: my-app\n"+
+		"probePreset: springboot2\n")
+
+	app := buildpack.ApplicationFromFlags(manifestPath, buildpack.ApplicationFlags{
+		Replicas:    -1,
+		ProbePreset: "springboot3",
+	})
+
+	if app.ProbePreset != "springboot3" {
+		t.Errorf("ProbePreset = %q, want the flag override %q", app.ProbePreset, "springboot3")
+	}
+}
+
+func TestApplicationFromFlagsKeepsManifestWhenUnset(t *testing.T) {
+	dir, err := ioutil.TempDir("", "application-from-flags-test-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	manifestPath := writeManifest(t, dir, "MANIFEST", ""+
+		"name: my-app\n"+
+		"arch: amd64\n"+
+		"replicas: 2\n")
+
+	app := buildpack.ApplicationFromFlags(manifestPath, buildpack.ApplicationFlags{Replicas: -1})
+
+	if app.Arch != "amd64" {
+		t.Errorf("Arch = %q, want the MANIFEST's value %q (no flag override)", app.Arch, "amd64")
+	}
+	if app.Replicas != 2 {
+		t.Errorf("Replica = %d, want the MANIFEST's value %d (no flag override)", app.Replicas, 2)
+	}
+}
+
+func TestApplicationFromFlagsMergesExtraLabels(t *testing.T) {
+	dir, err := ioutil.TempDir("", "application-from-flags-test-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	manifestPath := writeManifest(t, dir, "MANIFEST", ""+
+		"name: my-app\n"+
+		"labels:\n"+
+		"  team: payments\n")
+
+	app := buildpack.ApplicationFromFlags(manifestPath, buildpack.ApplicationFlags{
+		Replicas:    -1,
+		ExtraLabels: []string{"env=staging"},
+	})
+
+	if app.Labels["team"] != "payments" {
+		t.Errorf("Labels[team] = %q, want %q (from MANIFEST)", app.Labels["team"], "payments")
+	}
+	if app.Labels["env"] != "staging" {
+		t.Errorf("Labels[env] = %q, want %q (from --label)", app.Labels["env"], "staging")
+	}
+}
+
+func TestApplicationFromFlagsMergesAnnotationsFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "application-from-flags-test-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	manifestPath := writeManifest(t, dir, "MANIFEST", ""+
+		"name: my-app\n"+
+		"podAnnotations:\n"+
+		"  sidecar.istio.io/inject: \"true\"\n")
+
+	annotationsPath := writeManifest(t, dir, "annotations.yaml", ""+
+		"backup.example.com/policy: daily\n"+
+		"sidecar.istio.io/inject: \"false\"\n")
+
+	app := buildpack.ApplicationFromFlags(manifestPath, buildpack.ApplicationFlags{
+		Replicas:        -1,
+		AnnotationsFile: annotationsPath,
+	})
+
+	if app.PodAnnotations["backup.example.com/policy"] != "daily" {
+		t.Errorf("PodAnnotations[backup.example.com/policy] = %q, want %q (from --annotations-from-file)", app.PodAnnotations["backup.example.com/policy"], "daily")
+	}
+	if app.PodAnnotations["sidecar.istio.io/inject"] != "false" {
+		t.Errorf("PodAnnotations[sidecar.istio.io/inject] = %q, want %q (file wins over MANIFEST)", app.PodAnnotations["sidecar.istio.io/inject"], "false")
+	}
+}
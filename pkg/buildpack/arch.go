@@ -0,0 +1,56 @@
+package buildpack
+
+import (
+	"github.com/pkg/errors"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// archImageRepos maps an architecture (as reported by a Node's
+// status.nodeInfo.architecture, e.g. "amd64", "arm64") to the Docker image
+// repo to use for each of defaultImages's Name, for clusters where no single
+// multi-arch manifest list covers every image. Only amd64 and arm64 are
+// published today; any other architecture must be ruled out explicitly
+// rather than silently falling back to an image that won't run.
+var archImageRepos = map[string]map[string]string{
+	"amd64": {
+		"dev-s2i":          "quay.io/snowdrop/spring-boot-s2i",
+		"copy-supervisord": "quay.io/snowdrop/supervisord",
+	},
+	"arm64": {
+		"dev-s2i":          "quay.io/snowdrop/spring-boot-s2i-arm64",
+		"copy-supervisord": "quay.io/snowdrop/supervisord-arm64",
+	},
+}
+
+// ImageRepoForArch returns the Docker image repo to use for imageName on
+// arch, erroring if arch has no known image variant instead of silently
+// deploying an image that will crashloop on that node.
+func ImageRepoForArch(imageName string, arch string) (string, error) {
+	repos, ok := archImageRepos[arch]
+	if !ok {
+		return "", errors.Errorf("arch: no image variant configured for architecture '%s'", arch)
+	}
+	repo, ok := repos[imageName]
+	if !ok {
+		return "", errors.Errorf("arch: no '%s' image variant configured for architecture '%s'", imageName, arch)
+	}
+	return repo, nil
+}
+
+// DetectNodeArchitecture returns the architecture reported by an arbitrary
+// node in the cluster, for defaulting Application.Arch when neither the
+// MANIFEST nor --arch set it explicitly. Mixed-architecture clusters still
+// need --arch/MANIFEST `arch` to pick the variant matching where the dev pod
+// actually lands.
+func DetectNodeArchitecture(c kubernetes.Interface) (string, error) {
+	nodes, err := c.CoreV1().Nodes().List(metav1.ListOptions{Limit: 1})
+	if err != nil {
+		return "", errors.Wrap(err, "unable to list nodes")
+	}
+	if len(nodes.Items) == 0 {
+		return "", errors.Errorf("no nodes found in the cluster")
+	}
+	return nodes.Items[0].Status.NodeInfo.Architecture, nil
+}
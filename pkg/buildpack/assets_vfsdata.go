@@ -21,7 +21,7 @@ var Assets = func() http.FileSystem {
 	fs := vfsgen۰FS{
 		"/": &vfsgen۰DirInfo{
 			name:    "/",
-			modTime: time.Date(2018, 8, 16, 8, 21, 38, 253832101, time.UTC),
+			modTime: time.Date(2018, 8, 14, 17, 14, 16, 827264523, time.UTC),
 		},
 		"/java": &vfsgen۰DirInfo{
 			name:    "java",
@@ -29,24 +29,24 @@ var Assets = func() http.FileSystem {
 		},
 		"/java/imagestream": &vfsgen۰CompressedFileInfo{
 			name:             "imagestream",
-			modTime:          time.Date(2018, 8, 14, 17, 14, 16, 826950269, time.UTC),
-			uncompressedSize: 508,
+			modTime:          time.Date(2018, 8, 14, 17, 14, 16, 827264523, time.UTC),
+			uncompressedSize: 440,
 
-			compressedContent: []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x6c\x90\xc1\x6a\x33\x31\x0c\x84\xef\xfb\x14\x22\xf7\xc4\xfc\xff\xd1\x39\x95\xf6\xd2\x4b\x29\x0d\xf4\xae\xd8\xda\xd4\x8d\x6d\x09\xcb\x1b\x08\x26\xef\x5e\xe2\xdd\x94\xb4\xf4\x66\x3e\x69\x34\xe3\x41\x09\xef\x54\x34\x70\xb6\x10\x12\x1e\x68\xc3\x42\x59\x3f\xc2\x58\x37\x81\xcd\xe9\xdf\x70\x0c\xd9\x5b\x78\xbe\xce\x76\xb5\x10\xa6\x21\x51\x45\x8f\x15\xed\x00\x10\x71\x4f\x51\xaf\x2f\x00\x14\xb1\xd0\xda\xe6\x05\x13\x5d\x2e\x03\x40\xc6\x44\x9d\x74\xf5\x8d\xab\x90\xeb\x52\xe6\xe3\x24\xaf\x1c\x83\x3b\xcf\x07\x22\x3b\x8c\x16\x46\x8c\x4a\x03\x40\x6b\x61\x84\x45\xfb\xc4\xee\x48\xa5\xbf\xfb\xe9\x8a\x87\xee\xba\xfe\xb1\xf5\x90\x33\x57\xac\x81\xf3\x63\xf2\xda\x17\x01\xf0\x1b\x2e\x39\x01\x5c\xf2\x6a\x61\x55\xa6\xbc\xfe\xc4\x13\x5a\x33\x69\x31\xdd\xdd\xe8\xff\x60\xca\x94\xb7\x8e\x93\x84\x48\x7f\xce\x51\x95\xd2\x3e\xd2\x76\x3f\x85\xe8\xad\xf1\x24\x91\xcf\x89\x72\x55\xd3\x11\x8a\xac\xba\x55\x6b\x94\xfd\x92\x63\x2c\x9c\x6e\x01\xe6\x52\xef\x3e\xb5\xf0\x5f\x8d\xbd\x91\xf0\xa2\x0e\x49\xb8\xd4\xa5\x2d\x68\x33\x9c\xd7\x23\x56\xd2\xda\x41\xa1\x91\x0a\x65\x47\xf7\xb5\x02\xd4\xb3\x90\x85\x1d\x4f\xc5\xcd\xc5\xf6\x54\x5f\x01\x00\x00\xff\xff\xf6\xbc\xc2\x10\xfc\x01\x00\x00"),
+			compressedContent: []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\x5d\x50\x4d\x6b\xc3\x30\x0c\xbd\xe7\x57\x88\xde\x97\xb1\x6b\x6e\xa5\xbb\x0c\xca\x18\x2b\xec\xae\x39\x4a\x67\x62\x5b\xc6\x56\x0b\xc5\xf4\xbf\xcf\x51\xdc\xd2\xed\x26\xbf\x0f\xf9\x3d\x61\xb4\x5f\x94\xb2\xe5\x30\x80\xf5\x78\xa4\x9e\x23\x85\xfc\x63\x27\xe9\x2d\x3f\x9f\x5f\xba\xd9\x86\x71\x80\xb7\x85\x3b\x48\x22\xf4\x9d\x27\xc1\x11\x05\x87\x0e\xc0\xe1\x37\xb9\xbc\x4c\x00\x18\xe3\x00\xa5\xf4\xef\xe8\xe9\x7a\xad\x50\xa8\x83\x22\xea\xbe\xe1\x39\x92\x51\x2b\xf3\x7c\x8a\x1f\xec\xac\xb9\xac\x0b\x1c\x1b\x74\x6a\xd8\x2b\xb7\x5f\xde\xba\xa9\x14\x3b\x41\x5b\xf3\xca\x66\xa6\xa4\xb3\x72\x82\x47\x0d\xf0\xf4\x47\xb5\x0d\x81\x05\xa5\x16\xdb\xf9\x31\xab\xb0\x26\xbc\x83\x2d\x32\x80\xa9\xec\x00\x9b\xfa\xe7\x8e\xbd\xc7\xb0\x68\x37\xca\x95\x42\x61\x6c\xc6\x29\xb1\xbf\x39\xd6\x83\x3c\xa4\x68\xf8\xbf\xb6\x9f\x14\xb9\xb9\xad\x8f\x9c\xa4\x35\x85\xb2\x82\xab\xdc\xa1\x50\x16\x05\x12\x4d\x94\x28\x18\x7a\x3c\x49\xad\x77\x89\x55\x77\xe0\x53\x32\xd4\xdd\x53\xfd\x02\xb2\x97\x2f\x04\xb8\x01\x00\x00"),
 		},
 		"/java/route": &vfsgen۰CompressedFileInfo{
 			name:             "route",
-			modTime:          time.Date(2018, 8, 14, 17, 14, 16, 827158760, time.UTC),
-			uncompressedSize: 172,
+			modTime:          time.Date(2018, 8, 14, 17, 14, 16, 827264523, time.UTC),
+			uncompressedSize: 396,
 
-			compressedContent: []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x6c\xcd\x31\x0e\xc2\x30\x0c\x85\xe1\x3d\xa7\xf0\x09\x82\x58\x73\x08\x06\x90\xd8\x4d\xfb\x10\x16\x4d\x6c\x25\xa6\x4b\xd5\xbb\xa3\x28\x13\xa2\xf3\xff\x3e\x3d\x36\xb9\xa3\x36\xd1\x92\xa8\xea\xc7\x11\xd5\x50\xda\x4b\x9e\x1e\x45\x4f\xeb\x39\xbc\xa5\xcc\x89\xae\xbd\x85\x0c\xe7\x99\x9d\x53\x20\x2a\x9c\x91\x68\xdb\xe2\x85\x33\xf6\x3d\x10\x2d\xfc\xc0\xd2\x7a\x23\x62\xb3\xdf\xf8\x0f\x9a\x61\xea\x63\xd7\x41\xc6\xd1\x0d\x75\x95\x09\x47\xe2\x1b\x00\x00\xff\xff\xdd\xc7\x7e\xf9\xac\x00\x00\x00"),
+			compressedContent: []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\x03\x65\x90\x4d\x0e\x83\x20\x10\x85\xf7\x9e\x82\x13\xd8\x74\xcb\x09\xba\x68\x1a\x53\x4d\xf7\x53\x1d\x95\x54\x81\xc0\xd4\x0d\xf1\xee\xe5\xc7\x62\x6a\x77\x8f\x79\xdf\x83\xc7\x80\x16\x0f\x34\x56\x28\xc9\x99\x51\x6f\xc2\x52\x69\x94\x76\x14\x3d\x95\x42\x9d\x96\x73\xf1\x12\xb2\xe3\xec\x1e\xbc\x62\x46\x82\x0e\x08\x78\xc1\x98\x84\x19\x39\x73\xae\xbc\x79\xb1\xae\x7e\x32\xc1\x13\x27\x1b\x3c\xc6\x40\xeb\x68\xd6\x68\x16\xd1\x62\x66\xfe\x73\x56\x63\x1b\x32\xce\x89\x9e\x95\x17\x65\x29\x82\xa3\x17\x11\xcb\x13\xe7\x50\x76\x9b\x0a\x68\x05\x34\xc6\xa3\xf6\x22\xa2\x79\xb2\xa3\xa4\x52\x9f\xf4\x8b\xad\xcd\x6f\x8f\x63\xc5\x74\x7b\x03\x66\x40\xaa\x94\x49\x8f\x6b\x2f\xd2\x4d\x94\x8d\x98\x3e\x70\xc7\x92\xcd\xb5\x4e\x3d\xbe\x8b\x21\x34\xb3\x90\x40\x71\xe3\xd8\x0d\xb8\x87\x3e\x8e\xb3\xa3\x31\x8c\x01\x00\x00"),
 		},
 		"/java/service": &vfsgen۰CompressedFileInfo{
 			name:             "service",
-			modTime:          time.Date(2018, 8, 14, 17, 14, 16, 827320403, time.UTC),
-			uncompressedSize: 247,
+			modTime:          time.Date(2018, 8, 14, 17, 14, 16, 827264523, time.UTC),
+			uncompressedSize: 432,
 
-			compressedContent: []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x74\x8e\xc1\xaa\xc2\x30\x10\x45\xf7\xf9\x8a\xf9\x81\xf7\xc0\x6d\xb6\xee\xa5\xa0\xb8\x1f\xd3\x6b\x09\x26\x99\x30\x19\x0a\x52\xfa\xef\xd2\xe0\x42\x29\xee\x86\x39\xe7\xc0\xe5\x1a\xaf\xd0\x16\xa5\x78\x9a\x0f\xee\x11\xcb\xe8\xe9\x0c\x9d\x63\x80\xcb\x30\x1e\xd9\xd8\x3b\xa2\xc2\x19\x9e\x96\xe5\xff\xc4\x19\xeb\xea\x88\x12\xdf\x90\xda\xc6\x88\xb8\xd6\x6f\xb8\x0f\x5a\x45\xd8\xe4\x2a\x6a\xbd\xfa\xeb\x67\x57\x06\x51\x7b\x67\x55\xc5\x24\x48\xf2\x74\x39\x0e\xfd\x63\xac\x13\x6c\xd8\xb9\x0d\x09\xc1\x44\x7f\x2e\x18\x51\x93\x3c\x33\x8a\x05\x29\xf7\x38\x7d\xf0\x57\x00\x00\x00\xff\xff\x73\x21\x02\x3d\xf7\x00\x00\x00"),
+			compressedContent: []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\x03\x8d\x90\xcd\x0a\xc2\x30\x0c\xc7\xef\x7b\x8a\xbc\x80\x03\xaf\xbd\x7a\x56\x06\x8a\xf7\xd8\x66\xa3\xd8\xb5\xa5\x0d\x03\x29\x7b\x77\xd7\x81\xb3\xc3\x0d\xbc\x85\xdf\xff\x23\x21\xe8\xf5\x9d\x42\xd4\xce\x0a\x18\x8e\xd5\x53\x5b\x25\xe0\x4a\x61\xd0\x92\xaa\x9e\x18\x15\x32\x8a\x0a\xc0\x62\x4f\x02\x52\xaa\x2f\xd3\x30\x8e\x13\x31\xf8\x20\x13\xb3\x06\x80\xde\xaf\xc5\xdf\x40\xf4\x24\xb3\xd9\xbb\xc0\x73\xea\xf0\xb5\x34\x13\x2b\xa2\xd9\xb2\xf0\x0f\x0b\x8e\x9d\x74\x46\xc0\xed\xd4\xcc\x84\x31\x74\xc4\x4d\xe9\x5d\x3a\x52\xd2\x2d\xd4\x67\xb4\xd8\x51\x4f\x96\x97\xa2\x62\xe9\x5a\xdd\x5a\xbf\x91\xff\xe3\x90\x9d\xde\x94\xc8\xaa\x79\x8a\x64\x48\xb2\x0b\xbb\xaf\x53\xe4\x8d\x7b\xe5\x06\xe9\x6c\xab\xbb\x42\x7f\x03\xb7\xb8\x0d\x36\xb0\x01\x00\x00"),
 		},
 	}
 	fs["/"].(*vfsgen۰DirInfo).entries = []os.FileInfo{
@@ -6,19 +6,29 @@ import (
 
 	buildv1 "github.com/openshift/api/build/v1"
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
-	"github.com/snowdrop/spring-boot-cloud-devex/pkg/buildpack/types"
 	"log"
+
+	"github.com/snowdrop/spring-boot-cloud-devex/pkg/buildpack/types"
 )
 
 func CreateBuild(config *restclient.Config, appConfig types.Application) {
 	buildClient, err := buildclientsetv1.NewForConfig(config)
 	if err != nil {
+		log.Fatalf("Can't get BuildConfig Clientset: %s", err.Error())
+	}
+
+	if _, err := buildClient.BuildConfigs(appConfig.Namespace).Get(appConfig.Name, metav1.GetOptions{}); err == nil {
+		log.Printf("'%s' BuildConfig already exists, skipping", appConfig.Name)
+		return
+	} else if !apierrors.IsNotFound(err) {
+		log.Fatalf("Unable to check for an existing BuildConfig: %s", err.Error())
 	}
 
 	//_, errbuild := buildClient.Builds(appConfig.Namespace).Create(devBuild(appConfig.Name))
-	_, errbuild := buildClient.BuildConfigs(appConfig.Namespace).Create(devBuildConfig("dev-s2i", appConfig.Name))
+	_, errbuild := buildClient.BuildConfigs(appConfig.Namespace).Create(devBuildConfig("dev-s2i", appConfig.Name, appConfig.Incremental))
 	if errbuild != nil {
 		log.Fatalf("Unable to create Build: %s", errbuild.Error())
 	}
@@ -65,7 +75,7 @@ func devBuild(name string) *buildv1.Build {
 	}
 }
 
-func devBuildConfig(fromName string, toName string) *buildv1.BuildConfig {
+func devBuildConfig(fromName string, toName string, incremental bool) *buildv1.BuildConfig {
 	return &buildv1.BuildConfig{
 		ObjectMeta: metav1.ObjectMeta{
 			Name: toName,
@@ -87,6 +97,7 @@ func devBuildConfig(fromName string, toName string) *buildv1.BuildConfig {
 							Kind: "ImageStreamTag",
 							Name: fromName + ":latest",
 						},
+						Incremental: &incremental,
 					},
 				},
 			},
@@ -0,0 +1,28 @@
+package buildpack
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/snowdrop/spring-boot-cloud-devex/pkg/buildpack/types"
+)
+
+// DetectBuildTool resolves the "auto" BuildTool setting to a concrete value
+// by looking for Gradle build files in dir. Maven is kept as the fallback
+// since it's the tool the S2I image has historically assumed.
+func DetectBuildTool(dir string, buildTool string) string {
+	if buildTool != types.BuildToolAuto {
+		return buildTool
+	}
+
+	if fileExists(filepath.Join(dir, "build.gradle")) || fileExists(filepath.Join(dir, "build.gradle.kts")) {
+		return types.BuildToolGradle
+	}
+
+	return types.BuildToolMaven
+}
+
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
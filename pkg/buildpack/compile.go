@@ -0,0 +1,70 @@
+package buildpack
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/snowdrop/spring-boot-cloud-devex/pkg/common/config"
+	"github.com/snowdrop/spring-boot-cloud-devex/pkg/common/oc"
+)
+
+// logTailLines is how much output to surface when a build fails, so the
+// failure is visible without the caller needing to run `oc logs` themselves.
+const logTailLines = "50"
+
+// WaitForProgramExit polls the given supervisord program's status until it
+// leaves the STARTING/RUNNING states and returns its exit code. It's used by
+// `sd compile` to turn a failed in-pod build into a non-zero exit instead of
+// silently looking like it succeeded, since supervisorctl's "start" returns
+// as soon as the program has been launched.
+func WaitForProgramExit(podName string, program string) (int, error) {
+	for {
+		status, err := oc.ExecCommandAndReturn(oc.Command{Args: []string{"rsh", podName, config.SupervisordBin, config.SupervisordCtl, "status", program}})
+		if err != nil {
+			return -1, err
+		}
+
+		if strings.Contains(status, "RUNNING") || strings.Contains(status, "STARTING") {
+			time.Sleep(1 * time.Second)
+			continue
+		}
+
+		if strings.Contains(status, "EXITED") {
+			return parseExitCode(status), nil
+		}
+
+		return -1, fmt.Errorf("unexpected status for program '%s': %s", program, status)
+	}
+}
+
+// parseExitCode extracts the code from a supervisorctl status line such as
+// "compile-java    EXITED    Aug 09 12:00 PM (exit status 1)". Lines with no
+// exit status (e.g. a clean exit) are treated as success.
+func parseExitCode(status string) int {
+	marker := "exit status "
+	start := strings.Index(status, marker)
+	if start == -1 {
+		return 0
+	}
+	start += len(marker)
+
+	end := start
+	for end < len(status) && status[end] >= '0' && status[end] <= '9' {
+		end++
+	}
+
+	code, err := strconv.Atoi(status[start:end])
+	if err != nil {
+		return -1
+	}
+	return code
+}
+
+// TailLogs returns the last lines of the pod's logs, for surfacing alongside
+// a failed build.
+func TailLogs(podName string) string {
+	out, _ := oc.ExecCommandAndReturn(oc.Command{Args: []string{"logs", podName, "--tail=" + logTailLines}})
+	return out
+}
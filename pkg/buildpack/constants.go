@@ -8,3 +8,11 @@ const (
 
 var zero = int64(0)
 var deleteOptions = &v1.DeleteOptions{GracePeriodSeconds: &zero}
+
+// deleteOptionsWithGracePeriod builds DeleteOptions for the pod-terminating
+// deletions (the DeploymentConfig), so `sd clean --grace-period` and
+// `--force` can give the JVM a chance to run its shutdown hooks instead of
+// always force-killing it like deleteOptions does.
+func deleteOptionsWithGracePeriod(seconds int64) *v1.DeleteOptions {
+	return &v1.DeleteOptions{GracePeriodSeconds: &seconds}
+}
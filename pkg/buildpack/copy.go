@@ -0,0 +1,171 @@
+package buildpack
+
+import (
+	"archive/tar"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+	"golang.org/x/time/rate"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/scheme"
+	restclient "k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/remotecommand"
+)
+
+// CopyToPod streams localPath (a file or a directory, copied recursively)
+// into remotePath inside pod's first container, via `oc cp`/`kubectl cp`'s
+// own approach: exec a `tar x` on the other end and pipe a tar stream of
+// localPath to its stdin. Sharing this with the push pipeline means there's
+// one place that gets file modes and directory recursion right. It returns
+// the number of bytes written to the tar stream. limiter, if non-nil, caps
+// the stream's throughput; pass nil for no limit.
+func CopyToPod(clientset *kubernetes.Clientset, restConfig *restclient.Config, pod *corev1.Pod, localPath string, remotePath string, limiter *rate.Limiter) (int64, error) {
+	req := clientset.CoreV1().RESTClient().Post().
+		Resource("pods").
+		Name(pod.Name).
+		Namespace(pod.Namespace).
+		SubResource("exec")
+	req.VersionedParams(&corev1.PodExecOptions{
+		Container: pod.Spec.Containers[0].Name,
+		Command:   []string{"tar", "xmf", "-", "-C", remotePath},
+		Stdin:     true,
+		Stdout:    true,
+		Stderr:    true,
+	}, scheme.ParameterCodec)
+
+	executor, err := remotecommand.NewSPDYExecutor(restConfig, "POST", req.URL())
+	if err != nil {
+		return 0, errors.Wrap(err, "unable to create exec stream")
+	}
+
+	return StreamTarTo(executor, localPath, limiter)
+}
+
+// StreamTarTo tars up localPath and streams it to executor's stdin. Split
+// out from CopyToPod so tests can exercise the tar-building logic against a
+// fake remotecommand.Executor instead of a real SPDY connection. limiter, if
+// non-nil, caps the stream's throughput.
+func StreamTarTo(executor remotecommand.Executor, localPath string, limiter *rate.Limiter) (int64, error) {
+	pipeReader, pipeWriter := io.Pipe()
+	counter := &countingWriter{w: pipeWriter}
+
+	tarErrCh := make(chan error, 1)
+	go func() {
+		tarErrCh <- writeTar(counter, localPath)
+		pipeWriter.Close()
+	}()
+
+	var stdin io.Reader = pipeReader
+	if limiter != nil {
+		stdin = &throttledReader{r: pipeReader, limiter: limiter}
+	}
+
+	streamErr := executor.Stream(remotecommand.StreamOptions{
+		Stdin:  stdin,
+		Stdout: os.Stdout,
+		Stderr: os.Stderr,
+	})
+
+	if tarErr := <-tarErrCh; tarErr != nil {
+		return counter.n, errors.Wrap(tarErr, "unable to tar local path")
+	}
+	if streamErr != nil {
+		return counter.n, errors.Wrap(streamErr, "unable to stream tar to pod")
+	}
+	return counter.n, nil
+}
+
+// throttledReader wraps an io.Reader, blocking each Read so the long-run
+// average throughput doesn't exceed limiter's rate. Reads are capped to the
+// limiter's burst size, since WaitN refuses to wait for more tokens than a
+// limiter can ever hold.
+type throttledReader struct {
+	r       io.Reader
+	limiter *rate.Limiter
+}
+
+func (t *throttledReader) Read(p []byte) (int, error) {
+	if burst := t.limiter.Burst(); len(p) > burst {
+		p = p[:burst]
+	}
+	n, err := t.r.Read(p)
+	if n > 0 {
+		if waitErr := t.limiter.WaitN(context.Background(), n); waitErr != nil {
+			return n, errors.Wrap(waitErr, "throttle: rate limiter wait failed")
+		}
+	}
+	return n, err
+}
+
+// writeTar walks localPath and writes it to w as a tar stream, preserving
+// file modes; symlinks are written as links, not followed. A single file is written under its own base name, so
+// `tar xmf - -C remotePath` lands it directly at remotePath/<name>, matching
+// `oc cp`'s behavior.
+func writeTar(w io.Writer, localPath string) error {
+	tw := tar.NewWriter(w)
+	defer tw.Close()
+
+	localPath = filepath.Clean(localPath)
+	baseDir := filepath.Dir(localPath)
+
+	return filepath.Walk(localPath, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		relPath, err := filepath.Rel(baseDir, path)
+		if err != nil {
+			return err
+		}
+
+		link := ""
+		isLink := info.Mode()&os.ModeSymlink != 0
+		if isLink {
+			if link, err = os.Readlink(path); err != nil {
+				return err
+			}
+		}
+
+		header, err := tar.FileInfoHeader(info, link)
+		if err != nil {
+			return err
+		}
+		header.Name = filepath.ToSlash(relPath)
+
+		if err := tw.WriteHeader(header); err != nil {
+			return err
+		}
+
+		if info.IsDir() || isLink {
+			return nil
+		}
+
+		file, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+
+		_, err = io.Copy(tw, file)
+		return err
+	})
+}
+
+// countingWriter tracks bytes written so CopyToPod can report the tar
+// stream's size even though the actual Writer it wraps (a pipe) has no
+// notion of total size.
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
@@ -0,0 +1,117 @@
+package buildpack_test
+
+import (
+	"archive/tar"
+	"bytes"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/snowdrop/spring-boot-cloud-devex/pkg/buildpack"
+	"k8s.io/client-go/tools/remotecommand"
+)
+
+// fakeExecutor captures whatever is written to Stdin instead of opening a
+// real SPDY connection, so StreamTarTo's tar-building logic can be checked
+// without a cluster.
+type fakeExecutor struct {
+	stdin bytes.Buffer
+}
+
+func (f *fakeExecutor) Stream(options remotecommand.StreamOptions) error {
+	_, err := io.Copy(&f.stdin, options.Stdin)
+	return err
+}
+
+func TestStreamTarToWritesExecutableFileIntoTarStream(t *testing.T) {
+	dir, err := ioutil.TempDir("", "copy-test-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	filePath := filepath.Join(dir, "run.sh")
+	if err := ioutil.WriteFile(filePath, []byte("#!/bin/sh\necho hi\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	executor := &fakeExecutor{}
+	n, err := buildpack.StreamTarTo(executor, filePath, nil)
+	if err != nil {
+		t.Fatalf("StreamTarTo returned an error: %s", err.Error())
+	}
+	if n == 0 {
+		t.Error("expected a non-zero byte count")
+	}
+
+	tr := tar.NewReader(&executor.stdin)
+	header, err := tr.Next()
+	if err != nil {
+		t.Fatalf("tar stream didn't contain a readable entry: %s", err.Error())
+	}
+	if header.Name != "run.sh" {
+		t.Errorf("tar entry name = %q, want %q", header.Name, "run.sh")
+	}
+	if header.Mode&0100 == 0 {
+		t.Errorf("tar entry mode %o did not preserve the executable bit", header.Mode)
+	}
+
+	content, err := ioutil.ReadAll(tr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(content) != "#!/bin/sh\necho hi\n" {
+		t.Errorf("tar entry content = %q, want the original file content", string(content))
+	}
+
+	if _, err := tr.Next(); err != io.EOF {
+		t.Errorf("expected exactly one tar entry for a single file, got another: %v", err)
+	}
+}
+
+func TestStreamTarToWalksDirectoriesRecursively(t *testing.T) {
+	dir, err := ioutil.TempDir("", "copy-test-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	srcDir := filepath.Join(dir, "src")
+	nestedDir := filepath.Join(srcDir, "nested")
+	if err := os.MkdirAll(nestedDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(nestedDir, "file.txt"), []byte("content"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	executor := &fakeExecutor{}
+	if _, err := buildpack.StreamTarTo(executor, srcDir, nil); err != nil {
+		t.Fatalf("StreamTarTo returned an error: %s", err.Error())
+	}
+
+	var names []string
+	tr := tar.NewReader(&executor.stdin)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		names = append(names, header.Name)
+	}
+
+	want := []string{"src", "src/nested", "src/nested/file.txt"}
+	if len(names) != len(want) {
+		t.Fatalf("got tar entries %v, want %v", names, want)
+	}
+	for i := range want {
+		if names[i] != want[i] {
+			t.Errorf("tar entry[%d] = %q, want %q", i, names[i], want[i])
+		}
+	}
+}
@@ -1,6 +1,11 @@
 package buildpack
 
 import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
 	log "github.com/sirupsen/logrus"
 
 	restclient "k8s.io/client-go/rest"
@@ -9,79 +14,275 @@ import (
 	appsocpv1 "github.com/openshift/client-go/apps/clientset/versioned/typed/apps/v1"
 
 	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/wait"
 
 	"github.com/snowdrop/spring-boot-cloud-devex/pkg/buildpack/types"
-	"github.com/snowdrop/spring-boot-cloud-devex/pkg/common/oc"
+	cfg "github.com/snowdrop/spring-boot-cloud-devex/pkg/common/config"
 	"k8s.io/client-go/kubernetes"
 )
 
 const OdoLabelName = "io.openshift.odo"
 const OdoLabelValue = "inject-supervisord"
 
-func CreatePVC(clientset *kubernetes.Clientset, application types.Application, size string) {
-	if !oc.Exists("pvc", pvcName) {
-		quantity, err := resource.ParseQuantity(size)
-		if err != nil {
-			log.Fatal(err.Error())
-		}
-		pvc := &corev1.PersistentVolumeClaim{
-			ObjectMeta: metav1.ObjectMeta{
-				Name: pvcName,
-				Labels: map[string]string{
-					"app": application.Name,
-				},
+// Mount paths of the m2-data volume, which caches either the Maven local
+// repository or the Gradle home depending on the project's build tool.
+const (
+	m2MountPath     = "/tmp/artifacts"
+	gradleMountPath = "/root/.gradle"
+)
+
+// ContainerName returns the name of application's main container: its
+// MANIFEST-declared containerName override, or the application name if none
+// was set. logs/exec use this to target the right container, and it must
+// match BuildDeploymentConfig's own container Name/ContainerNames exactly.
+func ContainerName(application types.Application) string {
+	if application.ContainerName != "" {
+		return application.ContainerName
+	}
+	return application.Name
+}
+
+// pvcNameFor returns the name of the m2 cache PVC BuildDeploymentConfig
+// mounts: application.PVCName if a pre-provisioned, shared PVC was given via
+// `sd init --use-pvc`, or the default "m2-data" CreatePVC provisions
+// otherwise.
+func pvcNameFor(application types.Application) string {
+	if application.PVCName != "" {
+		return application.PVCName
+	}
+	return pvcName
+}
+
+// BuildPVC returns the m2-data PersistentVolumeClaim that would be created
+// for application, without touching the cluster.
+func BuildPVC(application types.Application, size string) (*corev1.PersistentVolumeClaim, error) {
+	quantity, err := resource.ParseQuantity(size)
+	if err != nil {
+		return nil, err
+	}
+
+	spec := corev1.PersistentVolumeClaimSpec{
+		Resources: corev1.ResourceRequirements{
+			Requests: corev1.ResourceList{
+				corev1.ResourceStorage: quantity,
 			},
-			Spec: corev1.PersistentVolumeClaimSpec{
-				Resources: corev1.ResourceRequirements{
-					Requests: corev1.ResourceList{
-						corev1.ResourceStorage: quantity,
-					},
-				},
-				AccessModes: []corev1.PersistentVolumeAccessMode{
-					corev1.ReadWriteOnce,
-				},
+		},
+		AccessModes: []corev1.PersistentVolumeAccessMode{
+			corev1.ReadWriteOnce,
+		},
+	}
+	if application.PVCStorageClass != "" {
+		spec.StorageClassName = &application.PVCStorageClass
+	}
+
+	return &corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: pvcName,
+			Labels: map[string]string{
+				"app": application.Name,
 			},
+		},
+		Spec: spec,
+	}, nil
+}
+
+// CreatePVC creates the m2 cache PVC if it doesn't already exist, returning
+// it either way -- the existing one when skipped, the newly created one
+// otherwise -- so callers can wait for it to bind without a second
+// round-trip.
+func CreatePVC(clientset *kubernetes.Clientset, application types.Application, size string) (*corev1.PersistentVolumeClaim, error) {
+	pvcs := clientset.CoreV1().PersistentVolumeClaims(application.Namespace)
+
+	if existing, err := pvcs.Get(pvcName, metav1.GetOptions{}); err == nil {
+		log.Infof("'%s' PVC already exists, skipping", pvcName)
+		return existing, nil
+	} else if !apierrors.IsNotFound(err) {
+		return nil, err
+	}
+
+	pvc, err := BuildPVC(application, size)
+	if err != nil {
+		return nil, err
+	}
+	return pvcs.Create(pvc)
+}
+
+// pvcBindPollInterval is how often WaitForPVCBound re-checks PVC status.
+const pvcBindPollInterval = 2 * time.Second
+
+// DefaultPVCBindTimeout bounds how long WaitForPVCBound waits by default,
+// matching the budget a dynamically-provisioned volume typically needs.
+const DefaultPVCBindTimeout = 2 * time.Minute
+
+// WaitForPVCBound polls pvcName until it reaches Bound, or returns
+// immediately if it uses a WaitForFirstConsumer storage class -- such a PVC
+// stays Pending by design until a pod mounting it is scheduled, which the
+// DC created right after CreatePVC triggers. A binding failure (most
+// commonly no provisioner configured for the class) is surfaced from the
+// PVC's own events instead of just timing out silently.
+func WaitForPVCBound(clientset *kubernetes.Clientset, namespace string, name string, timeout time.Duration) error {
+	pvcs := clientset.CoreV1().PersistentVolumeClaims(namespace)
+
+	pvc, err := pvcs.Get(name, metav1.GetOptions{})
+	if err != nil {
+		return errors.Wrapf(err, "unable to retrieve PVC '%s'", name)
+	}
+	if pvc.Status.Phase == corev1.ClaimBound {
+		return nil
+	}
+
+	if waitsForFirstConsumer(clientset, pvc.Spec.StorageClassName) {
+		log.Infof("PVC '%s' uses a WaitForFirstConsumer storage class; it stays Pending until a pod using it is scheduled", name)
+		return nil
+	}
+
+	err = wait.PollImmediate(pvcBindPollInterval, timeout, func() (bool, error) {
+		pvc, err := pvcs.Get(name, metav1.GetOptions{})
+		if err != nil {
+			return false, err
 		}
-		_, errPVC := clientset.CoreV1().PersistentVolumeClaims(application.Namespace).Create(pvc)
-		if errPVC != nil {
-			log.Fatal(errPVC.Error())
+
+		switch pvc.Status.Phase {
+		case corev1.ClaimBound:
+			return true, nil
+		case corev1.ClaimLost:
+			return false, errors.Errorf("PVC '%s' is Lost", name)
+		default:
+			return false, nil
 		}
-	} else {
-		log.Infof("'%s' PVC already exists, skipping", pvcName)
+	})
+
+	if err == wait.ErrWaitTimeout {
+		if reason := pvcProvisioningFailureReason(clientset, namespace, name); reason != "" {
+			return errors.Errorf("timed out after %s waiting for PVC '%s' to bind: %s", timeout, name, reason)
+		}
+		return errors.Errorf("timed out after %s waiting for PVC '%s' to bind", timeout, name)
 	}
+	return err
 }
 
-func DeletePVC(clientset *kubernetes.Clientset, application types.Application) {
-	if oc.Exists("pvc", pvcName) {
-		errPVC := clientset.CoreV1().PersistentVolumeClaims(application.Namespace).Delete(pvcName, deleteOptions)
-		if errPVC != nil {
-			log.Fatal(errPVC.Error())
+// waitsForFirstConsumer reports whether storageClassName (nil means the
+// cluster's default storage class) has VolumeBindingMode
+// WaitForFirstConsumer. It fails open (false) if the StorageClass can't be
+// resolved, since the immediate-binding wait is the safer default.
+func waitsForFirstConsumer(clientset *kubernetes.Clientset, storageClassName *string) bool {
+	name := ""
+	if storageClassName != nil {
+		name = *storageClassName
+	}
+
+	classes, err := clientset.StorageV1().StorageClasses().List(metav1.ListOptions{})
+	if err != nil {
+		return false
+	}
+
+	for _, class := range classes.Items {
+		isTarget := class.Name == name || (name == "" && class.Annotations["storageclass.kubernetes.io/is-default-class"] == "true")
+		if isTarget && class.VolumeBindingMode != nil {
+			return *class.VolumeBindingMode == storagev1.VolumeBindingWaitForFirstConsumer
 		}
 	}
+	return false
 }
 
-func CreateOrRetrieveDeploymentConfig(config *restclient.Config, application types.Application, commands string) *appsv1.DeploymentConfig {
+// pvcProvisioningFailureReason returns the message of the PVC's most recent
+// Warning event (e.g. "no persistent volumes available for this claim and
+// no storage class is set"), or "" if none is found.
+func pvcProvisioningFailureReason(clientset *kubernetes.Clientset, namespace string, name string) string {
+	events, err := clientset.CoreV1().Events(namespace).List(metav1.ListOptions{
+		FieldSelector: "involvedObject.kind=PersistentVolumeClaim,involvedObject.name=" + name,
+	})
+	if err != nil {
+		return ""
+	}
+
+	var latest *corev1.Event
+	for i := range events.Items {
+		event := &events.Items[i]
+		if event.Type != corev1.EventTypeWarning {
+			continue
+		}
+		if latest == nil || event.LastTimestamp.After(latest.LastTimestamp.Time) {
+			latest = event
+		}
+	}
+	if latest == nil {
+		return ""
+	}
+	return latest.Message
+}
+
+// ValidatePVC checks that application.PVCName exists in application.Namespace
+// and its access mode is compatible with being mounted into the dev pod
+// (ReadWriteOnce or ReadWriteMany), for `sd init --use-pvc`. Unlike
+// CreatePVC, it never creates anything -- a pre-provisioned, shared PVC that
+// doesn't exist yet is almost certainly a typo in the PVC name.
+func ValidatePVC(clientset *kubernetes.Clientset, application types.Application) error {
+	pvc, err := clientset.CoreV1().PersistentVolumeClaims(application.Namespace).Get(application.PVCName, metav1.GetOptions{})
+	if err != nil {
+		return errors.Wrapf(err, "PVC '%s'", application.PVCName)
+	}
+
+	for _, mode := range pvc.Spec.AccessModes {
+		if mode == corev1.ReadWriteOnce || mode == corev1.ReadWriteMany {
+			return nil
+		}
+	}
+	return errors.Errorf("PVC '%s' has no ReadWriteOnce/ReadWriteMany access mode, got %v", application.PVCName, pvc.Spec.AccessModes)
+}
+
+// DeletePVC deletes the m2 cache PVC (application.PVCName if --use-pvc was
+// given, else the default "m2-data"). It first waits for the pod that was
+// mounting it to be gone, so the PVC isn't yanked out from under a
+// still-terminating container; call it after DeleteDeploymentConfig.
+func DeletePVC(clientset *kubernetes.Clientset, application types.Application) {
+	name := pvcNameFor(application)
+	pvcs := clientset.CoreV1().PersistentVolumeClaims(application.Namespace)
+
+	if _, err := pvcs.Get(name, metav1.GetOptions{}); err != nil {
+		return
+	}
+
+	if err := WaitForPodDeleted(clientset, application, 60*time.Second); err != nil {
+		log.Warnf("Proceeding with PVC deletion: %s", err.Error())
+	}
+
+	if err := pvcs.Delete(name, deleteOptions); err != nil && !apierrors.IsNotFound(err) {
+		log.Fatal(err.Error())
+	}
+}
+
+func CreateOrRetrieveDeploymentConfig(config *restclient.Config, application types.Application, commands string, noCache bool) *appsv1.DeploymentConfig {
 	deploymentConfigV1client := getAppsClient(config)
 
 	deploymentConfigs := deploymentConfigV1client.DeploymentConfigs(application.Namespace)
 
-	var dc *appsv1.DeploymentConfig
-	var errCreate error
-	if oc.Exists("dc", application.Name) {
-		dc, errCreate = deploymentConfigs.Get(application.Name, metav1.GetOptions{})
+	dc, err := deploymentConfigs.Get(application.Name, metav1.GetOptions{})
+	if err == nil {
 		log.Infof("'%s' DeploymentConfig already exists, skipping", application.Name)
-	} else {
-		dc, errCreate = deploymentConfigs.Create(javaDeploymentConfig(application, commands))
+		return dc
+	}
+	if !apierrors.IsNotFound(err) {
+		log.Fatalf("DeploymentConfig not created: %s", err.Error())
 	}
-	if errCreate != nil {
-		log.Fatalf("DeploymentConfig not created: %s", errCreate.Error())
+
+	dc, err = deploymentConfigs.Create(BuildDeploymentConfig(application, commands, noCache))
+	if err != nil {
+		log.Fatalf("DeploymentConfig not created: %s", err.Error())
 	}
 	return dc
 }
 
 func getAppsClient(config *restclient.Config) *appsocpv1.AppsV1Client {
+	if err := cfg.RequireServedGroupVersion(config, appsv1.SchemeGroupVersion.String(), "DeploymentConfig"); err != nil {
+		log.Fatal(err.Error())
+	}
+
 	deploymentConfigV1client, err := appsocpv1.NewForConfig(config)
 	if err != nil {
 		log.Fatalf("Can't get DeploymentConfig Clientset: %s", err.Error())
@@ -89,132 +290,325 @@ func getAppsClient(config *restclient.Config) *appsocpv1.AppsV1Client {
 	return deploymentConfigV1client
 }
 
-func DeleteDeploymentConfig(config *restclient.Config, application types.Application) {
-	if oc.Exists("dc", application.Name) {
-		errPVC := getAppsClient(config).DeploymentConfigs(application.Namespace).Delete(application.Name, deleteOptions)
-		if errPVC != nil {
-			log.Fatal(errPVC.Error())
-		}
+// DeleteDeploymentConfig deletes the DeploymentConfig, giving its pod
+// gracePeriodSeconds to shut down cleanly (e.g. run JVM shutdown hooks)
+// before being killed.
+func DeleteDeploymentConfig(config *restclient.Config, application types.Application, gracePeriodSeconds int64) {
+	errDc := getAppsClient(config).DeploymentConfigs(application.Namespace).Delete(application.Name, deleteOptionsWithGracePeriod(gracePeriodSeconds))
+	if errDc != nil && !apierrors.IsNotFound(errDc) {
+		log.Fatal(errDc.Error())
 	}
 }
 
-func javaDeploymentConfig(application types.Application, commands string) *appsv1.DeploymentConfig {
+// BuildDeploymentConfig returns the DeploymentConfig that would be created
+// for application, without touching the cluster.
+func BuildDeploymentConfig(application types.Application, commands string, noCache bool) *appsv1.DeploymentConfig {
 	if commands == "" {
-		commands = "run-java:/usr/local/s2i/run;compile-java:/usr/local/s2i/assemble;build:/deployments/buildapp"
+		commands = cfg.DefaultCommands(application.BuildTool)
+	}
+
+	cacheMountPath := m2MountPath
+	if application.BuildTool == types.BuildToolGradle {
+		cacheMountPath = gradleMountPath
+	}
+
+	var volumeMounts []corev1.VolumeMount
+	var volumes []corev1.Volume
+	if application.MountSource {
+		volumeMounts = append(volumeMounts, corev1.VolumeMount{
+			Name:      "shared-data",
+			MountPath: "/var/lib/supervisord",
+		})
+		volumes = append(volumes, corev1.Volume{
+			Name: "shared-data",
+			VolumeSource: corev1.VolumeSource{
+				EmptyDir: &corev1.EmptyDirVolumeSource{},
+			},
+		})
+	}
+	if !noCache {
+		volumeMounts = append(volumeMounts, corev1.VolumeMount{
+			Name:      "m2-data",
+			MountPath: cacheMountPath,
+		})
+		volumes = append(volumes, corev1.Volume{
+			Name: "m2-data",
+			VolumeSource: corev1.VolumeSource{
+				PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+					ClaimName: pvcNameFor(application),
+				},
+			},
+		})
+	}
+
+	if application.MavenSettings != "" {
+		volumeMounts = append(volumeMounts, corev1.VolumeMount{
+			Name:      "maven-settings",
+			MountPath: mavenSettingsMountPath,
+		})
+		volumes = append(volumes, corev1.Volume{
+			Name: "maven-settings",
+			VolumeSource: corev1.VolumeSource{
+				ConfigMap: &corev1.ConfigMapVolumeSource{
+					LocalObjectReference: corev1.LocalObjectReference{Name: mavenSettingsConfigMapName},
+				},
+			},
+		})
+	}
+
+	hostAliases := make([]corev1.HostAlias, 0, len(application.HostAliases))
+	for _, hostAlias := range application.HostAliases {
+		hostAliases = append(hostAliases, corev1.HostAlias{
+			IP:        hostAlias.IP,
+			Hostnames: hostAlias.Hostnames,
+		})
+	}
+
+	var dnsConfig *corev1.PodDNSConfig
+	if len(application.DNSConfig.Nameservers) > 0 || len(application.DNSConfig.Searches) > 0 {
+		dnsConfig = &corev1.PodDNSConfig{
+			Nameservers: application.DNSConfig.Nameservers,
+			Searches:    application.DNSConfig.Searches,
+		}
+	}
+
+	podSecurityContext, containerSecurityContext := securityContexts(application.SecurityContext)
+
+	// containerName is the main container's name, and the ImageChange
+	// triggers' ContainerNames must match it exactly for a rollout to
+	// actually pick up a new image.
+	containerName := ContainerName(application)
+
+	var containerCommand, containerArgs []string
+	if application.MountSource {
+		containerCommand = []string{"/var/lib/supervisord/bin/supervisord"}
+		containerArgs = []string{"-c", "/var/lib/supervisord/conf/supervisor.conf"}
+	}
+
+	containerPorts := []corev1.ContainerPort{
+		{
+			Name:          application.PortName,
+			ContainerPort: application.Port,
+			Protocol:      corev1.ProtocolTCP,
+		},
+	}
+
+	// probePortName is the port readiness/liveness checks target. When a
+	// separate management port is declared (e.g. for Spring Boot Actuator),
+	// probes hit it instead of the traffic port, so health checks don't
+	// compete with application load.
+	probePortName := application.PortName
+	if application.ManagementPort != 0 {
+		probePortName = application.ManagementPortName
+		containerPorts = append(containerPorts, corev1.ContainerPort{
+			Name:          application.ManagementPortName,
+			ContainerPort: application.ManagementPort,
+			Protocol:      corev1.ProtocolTCP,
+		})
+	}
+
+	probeSettings := ProbeSettingsFor(application.ProbePreset)
+	readinessProbe := &corev1.Probe{
+		Handler: corev1.Handler{
+			HTTPGet: &corev1.HTTPGetAction{
+				Path: probeSettings.ReadinessPath,
+				Port: intstr.FromString(probePortName),
+			},
+		},
+		InitialDelaySeconds: probeSettings.InitialDelaySeconds,
+		PeriodSeconds:       probeSettings.PeriodSeconds,
+	}
+	livenessProbe := &corev1.Probe{
+		Handler: corev1.Handler{
+			HTTPGet: &corev1.HTTPGetAction{
+				Path: probeSettings.LivenessPath,
+				Port: intstr.FromString(probePortName),
+			},
+		},
+		InitialDelaySeconds: probeSettings.InitialDelaySeconds,
+		PeriodSeconds:       probeSettings.PeriodSeconds,
+	}
+
+	// preStopCommand defaults to a graceful Actuator shutdown call when a
+	// management port is declared, so a rolling update or `sd clean`
+	// doesn't drop in-flight requests; the MANIFEST's lifecycle.preStop
+	// overrides it outright.
+	preStopCommand := application.Lifecycle.PreStop
+	if len(preStopCommand) == 0 && application.ManagementPort != 0 {
+		preStopCommand = []string{
+			"curl", "-s", "-X", "POST",
+			fmt.Sprintf("http://localhost:%d/actuator/shutdown", application.ManagementPort),
+		}
+	}
+	var lifecycle *corev1.Lifecycle
+	if len(preStopCommand) > 0 {
+		lifecycle = &corev1.Lifecycle{
+			PreStop: &corev1.Handler{
+				Exec: &corev1.ExecAction{Command: preStopCommand},
+			},
+		}
+	}
+
+	// devImage, when set, makes the container run a prebuilt image directly
+	// and drops the S2I ImageChange trigger, since there is no ImageStream
+	// to watch for it.
+	containerImage := "dev-s2i:latest"
+	initContainers := []corev1.Container{*supervisordInitContainer(application.SupervisordName, commands, application.SupervisordPort)}
+	var triggers []appsv1.DeploymentTriggerPolicy
+	if application.GitURL != "" {
+		// Git-source build: the container runs the image the BuildConfig
+		// produces, with no supervisord init container, watched via its own
+		// ImageStreamTag instead of the shared dev-s2i stream.
+		containerImage = application.Name + ":latest"
+		initContainers = nil
+		triggers = append(triggers, appsv1.DeploymentTriggerPolicy{
+			Type: "ImageChange",
+			ImageChangeParams: &appsv1.DeploymentTriggerImageChangeParams{
+				Automatic:      true,
+				ContainerNames: []string{containerName},
+				From: corev1.ObjectReference{
+					Kind: "ImageStreamTag",
+					Name: application.Name + ":latest",
+				},
+			},
+		})
+	} else if !application.MountSource {
+		// The image runs as-is via its own entrypoint: no supervisord to
+		// inject, and no dev-s2i ImageStream to watch for changes.
+		containerImage = application.DevImage
+		initContainers = nil
+	} else if application.DevImage != "" {
+		// devImage already contains supervisord, so there's nothing to copy
+		// in via the init container and no ImageStream to trigger a redeploy.
+		containerImage = application.DevImage
+		initContainers = nil
+	} else {
+		triggers = append(triggers, appsv1.DeploymentTriggerPolicy{
+			Type: "ImageChange",
+			ImageChangeParams: &appsv1.DeploymentTriggerImageChangeParams{
+				Automatic: true,
+				ContainerNames: []string{
+					application.SupervisordName,
+				},
+				From: corev1.ObjectReference{
+					Kind: "ImageStreamTag",
+					Name: application.SupervisordName + ":latest",
+				},
+			},
+		}, appsv1.DeploymentTriggerPolicy{
+			Type: "ImageChange",
+			ImageChangeParams: &appsv1.DeploymentTriggerImageChangeParams{
+				Automatic: true,
+				ContainerNames: []string{
+					containerName,
+				},
+				From: corev1.ObjectReference{
+					Kind: "ImageStreamTag",
+					Name: "dev-s2i:latest",
+				},
+			},
+		})
 	}
+
 	return &appsv1.DeploymentConfig{
 		ObjectMeta: metav1.ObjectMeta{
 			Name: application.Name,
-			Labels: map[string]string{
+			Labels: MergeLabels(application.Labels, map[string]string{
 				"app":        application.Name,
 				OdoLabelName: OdoLabelValue,
-			},
+			}),
 		},
 		Spec: appsv1.DeploymentConfigSpec{
-			Replicas: 1,
+			Replicas: int32(application.Replicas),
 			Selector: map[string]string{
 				"app":              application.Name,
 				"deploymentconfig": application.Name,
 			},
 			Strategy: appsv1.DeploymentStrategy{
-				Type: appsv1.DeploymentStrategyTypeRolling,
+				Type: deploymentStrategyType(application.Strategy),
 			},
 			Template: &corev1.PodTemplateSpec{
 				ObjectMeta: metav1.ObjectMeta{
 					Name: application.Name,
-					Labels: map[string]string{
+					Labels: MergeLabels(application.Labels, map[string]string{
 						"app":              application.Name,
 						"deploymentconfig": application.Name,
-					},
+					}),
+					Annotations: application.PodAnnotations,
 				},
 				Spec: corev1.PodSpec{
-					InitContainers: []corev1.Container{*supervisordInitContainer(application.SupervisordName, commands)},
+					ServiceAccountName: application.ServiceAccount,
+					HostAliases:        hostAliases,
+					DNSConfig:          dnsConfig,
+					SecurityContext:    podSecurityContext,
+					InitContainers:     initContainers,
 					Containers: []corev1.Container{
 						{
-							Image: "dev-s2i:latest",
-							Name:  application.Name,
-							Ports: []corev1.ContainerPort{
-								{
-									ContainerPort: application.Port,
-									Protocol:      corev1.ProtocolTCP,
-								},
-							},
-							Env: populateEnvVar(application),
+							Image:           containerImage,
+							Name:            containerName,
+							Ports:           containerPorts,
+							ReadinessProbe:  readinessProbe,
+							LivenessProbe:   livenessProbe,
+							SecurityContext: containerSecurityContext,
+							Env:             populateEnvVar(application),
 							/*							Resources: corev1.ResourceRequirements{
 														Limits: corev1.ResourceList{
 															corev1.ResourceCPU: resource.MustParse(appConfig.Cpu),
 															corev1.ResourceMemory: resource.MustParse(appConfig.Memory),
 														},
 													},*/
-							VolumeMounts: []corev1.VolumeMount{
-								{
-									Name:      "shared-data",
-									MountPath: "/var/lib/supervisord",
-								},
-								{
-									Name:      "m2-data",
-									MountPath: "/tmp/artifacts",
-								},
-							},
-							Command: []string{
-								"/var/lib/supervisord/bin/supervisord",
-							},
-							Args: []string{
-								"-c",
-								"/var/lib/supervisord/conf/supervisor.conf",
-							},
-						},
-					},
-					Volumes: []corev1.Volume{
-						{
-							Name: "shared-data",
-							VolumeSource: corev1.VolumeSource{
-								EmptyDir: &corev1.EmptyDirVolumeSource{},
-							},
-						},
-						{
-							Name: "m2-data",
-							VolumeSource: corev1.VolumeSource{
-								PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
-									ClaimName: "m2-data",
-								},
-							},
-						},
-					},
-				},
-			},
-			Triggers: []appsv1.DeploymentTriggerPolicy{
-				{
-					Type: "ImageChange",
-					ImageChangeParams: &appsv1.DeploymentTriggerImageChangeParams{
-						Automatic: true,
-						ContainerNames: []string{
-							application.SupervisordName,
-						},
-						From: corev1.ObjectReference{
-							Kind: "ImageStreamTag",
-							Name: application.SupervisordName + ":latest",
-						},
-					},
-				},
-				{
-					Type: "ImageChange",
-					ImageChangeParams: &appsv1.DeploymentTriggerImageChangeParams{
-						Automatic: true,
-						ContainerNames: []string{
-							application.Name,
-						},
-						From: corev1.ObjectReference{
-							Kind: "ImageStreamTag",
-							Name: "dev-s2i:latest",
+							VolumeMounts: volumeMounts,
+							Command:      containerCommand,
+							Args:         containerArgs,
+							Lifecycle:    lifecycle,
 						},
 					},
+					Volumes: volumes,
 				},
 			},
+			Triggers: triggers,
 		},
 	}
 }
 
+// deploymentStrategyType maps the MANIFEST's Strategy value to one of
+// OpenShift's DeploymentConfig strategies, defaulting to Rolling for any
+// unrecognized value.
+func deploymentStrategyType(strategy string) appsv1.DeploymentStrategyType {
+	if strategy == string(appsv1.DeploymentStrategyTypeRecreate) {
+		return appsv1.DeploymentStrategyTypeRecreate
+	}
+	return appsv1.DeploymentStrategyTypeRolling
+}
+
+// securityContexts renders sc into the pod- and container-level security
+// contexts applied to the DC, so restricted SCC/PSA namespaces admit the dev
+// pod without the caller having to set anything in the MANIFEST.
+func securityContexts(sc types.SecurityContext) (*corev1.PodSecurityContext, *corev1.SecurityContext) {
+	podSecurityContext := &corev1.PodSecurityContext{
+		RunAsNonRoot: &sc.RunAsNonRoot,
+	}
+	if sc.FSGroup != 0 {
+		podSecurityContext.FSGroup = &sc.FSGroup
+	}
+
+	containerSecurityContext := &corev1.SecurityContext{
+		RunAsNonRoot: &sc.RunAsNonRoot,
+	}
+	if sc.RunAsUser != 0 {
+		containerSecurityContext.RunAsUser = &sc.RunAsUser
+	}
+	if len(sc.DropCapabilities) > 0 {
+		drop := make([]corev1.Capability, 0, len(sc.DropCapabilities))
+		for _, c := range sc.DropCapabilities {
+			drop = append(drop, corev1.Capability(c))
+		}
+		containerSecurityContext.Capabilities = &corev1.Capabilities{Drop: drop}
+	}
+
+	return podSecurityContext, containerSecurityContext
+}
+
 func populateEnvVar(application types.Application) []corev1.EnvVar {
 	envs := []corev1.EnvVar{}
 
@@ -245,7 +639,7 @@ func contains(envs []corev1.EnvVar, key string) bool {
 	return false
 }
 
-func supervisordInitContainer(name string, commands string) *corev1.Container {
+func supervisordInitContainer(name string, commands string, port int32) *corev1.Container {
 	return &corev1.Container{
 		Name:  name,
 		Image: name + ":latest",
@@ -261,6 +655,10 @@ func supervisordInitContainer(name string, commands string) *corev1.Container {
 				Name:  "CMDS",
 				Value: commands,
 			},
+			{
+				Name:  "PORT",
+				Value: strconv.Itoa(int(port)),
+			},
 		},
 	}
 }
@@ -0,0 +1,71 @@
+package buildpack
+
+import (
+	"reflect"
+	"strings"
+
+	restclient "k8s.io/client-go/rest"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/diff"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/snowdrop/spring-boot-cloud-devex/pkg/buildpack/types"
+)
+
+// DiffDeploymentConfig compares the desired DeploymentConfig for application
+// against the one live in the cluster and returns a human readable diff of
+// their specs. An empty string means they match.
+func DiffDeploymentConfig(config *restclient.Config, application types.Application, commands string, noCache bool) string {
+	desired := BuildDeploymentConfig(application, commands, noCache)
+
+	live, err := getAppsClient(config).DeploymentConfigs(application.Namespace).Get(application.Name, metav1.GetOptions{})
+	if err != nil {
+		return "DeploymentConfig '" + application.Name + "' does not exist yet"
+	}
+
+	if reflect.DeepEqual(desired.Spec, live.Spec) {
+		return ""
+	}
+	return diff.ObjectDiff(desired.Spec, live.Spec)
+}
+
+// DiffService compares the desired Service for application against the one
+// live in the cluster and returns a human readable diff of their specs.
+func DiffService(clientset *kubernetes.Clientset, application types.Application) string {
+	desired := BuildService(application)
+
+	live, err := clientset.CoreV1().Services(application.Namespace).Get(application.Name, metav1.GetOptions{})
+	if err != nil {
+		return "Service '" + application.Name + "' does not exist yet"
+	}
+
+	if reflect.DeepEqual(desired.Spec, live.Spec) {
+		return ""
+	}
+	return diff.ObjectDiff(desired.Spec, live.Spec)
+}
+
+// DiffRoute compares every desired Route for application (or the single
+// default one) against what's live in the cluster and returns a human
+// readable diff of their specs, one section per Route.
+func DiffRoute(config *restclient.Config, application types.Application) string {
+	routeV1Client := getClient(config)
+
+	var sections []string
+	for _, route := range applicationRoutes(application) {
+		desired := BuildRoute(application, route)
+
+		live, err := routeV1Client.Routes(application.Namespace).Get(route.Name, metav1.GetOptions{})
+		if err != nil {
+			sections = append(sections, "Route '"+route.Name+"' does not exist yet")
+			continue
+		}
+
+		if !reflect.DeepEqual(desired.Spec, live.Spec) {
+			sections = append(sections, diff.ObjectDiff(desired.Spec, live.Spec))
+		}
+	}
+
+	return strings.Join(sections, "\n")
+}
@@ -0,0 +1,111 @@
+package buildpack
+
+import (
+	log "github.com/sirupsen/logrus"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	restclient "k8s.io/client-go/rest"
+	"k8s.io/client-go/util/retry"
+
+	"github.com/snowdrop/spring-boot-cloud-devex/pkg/buildpack/types"
+)
+
+// EnvVarSource distinguishes an env var coming from the MANIFEST (and
+// therefore reset on the next `sd init`) from one set ad hoc on the live
+// DeploymentConfig outside the MANIFEST (`sd env --unset` removes either
+// kind).
+type EnvVarSource string
+
+const (
+	EnvVarSourceManifest EnvVarSource = "MANIFEST"
+	EnvVarSourceAdHoc    EnvVarSource = "ad-hoc"
+)
+
+// EnvVarEntry is one container env var as currently deployed, labeled with
+// where it came from.
+type EnvVarEntry struct {
+	Name   string
+	Value  string
+	Source EnvVarSource
+}
+
+// ListEnv returns the application container's env vars from the live DC, in
+// the order they're defined, labeling each as MANIFEST-managed or ad-hoc.
+func ListEnv(config *restclient.Config, application types.Application) []EnvVarEntry {
+	dc, err := getAppsClient(config).DeploymentConfigs(application.Namespace).Get(application.Name, metav1.GetOptions{})
+	if err != nil {
+		log.Fatalf("Unable to retrieve DeploymentConfig: %s", err.Error())
+	}
+
+	manifestEnv := map[string]bool{}
+	for _, e := range application.Env {
+		manifestEnv[e.Name] = true
+	}
+
+	entries := []EnvVarEntry{}
+	for _, container := range dc.Spec.Template.Spec.Containers {
+		if container.Name != application.Name {
+			continue
+		}
+		for _, env := range container.Env {
+			source := EnvVarSourceAdHoc
+			if manifestEnv[env.Name] {
+				source = EnvVarSourceManifest
+			}
+			entries = append(entries, EnvVarEntry{Name: env.Name, Value: env.Value, Source: source})
+		}
+	}
+	return entries
+}
+
+// UnsetEnv removes name from the application container's env and, if it was
+// actually present, updates the DeploymentConfig to roll out the change.
+// The get-modify-update is retried on a conflict (e.g. the operator updated
+// the DC between the Get and the Update) instead of failing outright.
+func UnsetEnv(config *restclient.Config, application types.Application, name string) {
+	client := getAppsClient(config).DeploymentConfigs(application.Namespace)
+
+	notSet := false
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		dc, err := client.Get(application.Name, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+
+		changed := false
+		for i := range dc.Spec.Template.Spec.Containers {
+			container := &dc.Spec.Template.Spec.Containers[i]
+			if container.Name != application.Name {
+				continue
+			}
+
+			filtered := make([]corev1.EnvVar, 0, len(container.Env))
+			for _, env := range container.Env {
+				if env.Name == name {
+					changed = true
+					continue
+				}
+				filtered = append(filtered, env)
+			}
+			container.Env = filtered
+		}
+
+		if !changed {
+			notSet = true
+			return nil
+		}
+
+		_, err = client.Update(dc)
+		return err
+	})
+	if err != nil {
+		log.Fatalf("Unable to update DeploymentConfig: %s", err.Error())
+	}
+
+	if notSet {
+		log.Infof("'%s' is not set, nothing to do", name)
+		return
+	}
+	log.Infof("Unset '%s' and rolled out the DeploymentConfig", name)
+}
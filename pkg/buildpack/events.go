@@ -0,0 +1,67 @@
+package buildpack
+
+import (
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/snowdrop/spring-boot-cloud-devex/pkg/buildpack/types"
+)
+
+// EventFilter narrows ListEvents/WatchEvents to events matching Type
+// (exact, e.g. "Warning") and/or a Reason substring (e.g. "FailedMount").
+// A zero EventFilter matches everything.
+type EventFilter struct {
+	Type   string
+	Reason string
+}
+
+// Matches reports whether event satisfies filter.
+func (filter EventFilter) Matches(event *corev1.Event) bool {
+	if filter.Type != "" && event.Type != filter.Type {
+		return false
+	}
+	if filter.Reason != "" && !strings.Contains(event.Reason, filter.Reason) {
+		return false
+	}
+	return true
+}
+
+// ListEvents returns application's namespace's events matching filter.
+func ListEvents(clientset kubernetes.Interface, application types.Application, filter EventFilter) ([]corev1.Event, error) {
+	list, err := clientset.CoreV1().Events(application.Namespace).List(metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	var matched []corev1.Event
+	for _, event := range list.Items {
+		if filter.Matches(&event) {
+			matched = append(matched, event)
+		}
+	}
+	return matched, nil
+}
+
+// WatchEvents streams application's namespace's events matching filter to
+// handle, until the watch's channel closes.
+func WatchEvents(clientset kubernetes.Interface, application types.Application, filter EventFilter, handle func(corev1.Event)) error {
+	w, err := clientset.CoreV1().Events(application.Namespace).Watch(metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+	defer w.Stop()
+
+	for val := range w.ResultChan() {
+		event, ok := val.Object.(*corev1.Event)
+		if !ok {
+			continue
+		}
+		if filter.Matches(event) {
+			handle(*event)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,33 @@
+package buildpack_test
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/snowdrop/spring-boot-cloud-devex/pkg/buildpack"
+)
+
+func TestEventFilterMatches(t *testing.T) {
+	cases := []struct {
+		name   string
+		filter buildpack.EventFilter
+		event  corev1.Event
+		want   bool
+	}{
+		{"empty filter matches anything", buildpack.EventFilter{}, corev1.Event{Type: "Normal", Reason: "Pulled"}, true},
+		{"type matches", buildpack.EventFilter{Type: "Warning"}, corev1.Event{Type: "Warning", Reason: "FailedMount"}, true},
+		{"type mismatches", buildpack.EventFilter{Type: "Warning"}, corev1.Event{Type: "Normal", Reason: "Pulled"}, false},
+		{"reason substring matches", buildpack.EventFilter{Reason: "Mount"}, corev1.Event{Type: "Warning", Reason: "FailedMount"}, true},
+		{"reason substring mismatches", buildpack.EventFilter{Reason: "BackOff"}, corev1.Event{Type: "Warning", Reason: "FailedMount"}, false},
+		{"type and reason both required", buildpack.EventFilter{Type: "Warning", Reason: "Mount"}, corev1.Event{Type: "Normal", Reason: "FailedMount"}, false},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := c.filter.Matches(&c.event); got != c.want {
+				t.Errorf("Matches() = %v, want %v", got, c.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,42 @@
+package buildpack
+
+import (
+	log "github.com/sirupsen/logrus"
+
+	"github.com/snowdrop/spring-boot-cloud-devex/pkg/common/oc"
+)
+
+// ApplyExtraManifests applies each raw YAML file in paths, in order, via the
+// oc client, then labels its resources with OdoLabelName=OdoLabelValue so
+// `sd clean` can find and remove them again alongside the built-in
+// resources. A failure on one file is reported and doesn't stop the rest
+// from being applied.
+func ApplyExtraManifests(paths []string) {
+	for _, path := range paths {
+		log.Infof("Applying extra manifest '%s'", path)
+
+		out, err := oc.ExecCommandAndReturn(oc.Command{Args: []string{"apply", "-f", path}})
+		if err != nil {
+			log.Errorf("Failed to apply '%s': %s", path, err.Error())
+			continue
+		}
+		log.Info(out)
+
+		labelArg := OdoLabelName + "=" + OdoLabelValue
+		if _, err := oc.ExecCommandAndReturn(oc.Command{Args: []string{"label", "-f", path, labelArg, "--overwrite"}}); err != nil {
+			log.Errorf("Failed to label resources from '%s': %s", path, err.Error())
+		}
+	}
+}
+
+// DeleteExtraManifests removes each raw YAML file's resources, in reverse
+// order, so `sd clean` also tears down anything created via extraManifests.
+func DeleteExtraManifests(paths []string) {
+	for i := len(paths) - 1; i >= 0; i-- {
+		path := paths[i]
+		log.Infof("Deleting extra manifest '%s'", path)
+		if _, err := oc.ExecCommandAndReturn(oc.Command{Args: []string{"delete", "-f", path, "--ignore-not-found"}}); err != nil {
+			log.Errorf("Failed to delete '%s': %s", path, err.Error())
+		}
+	}
+}
@@ -0,0 +1,111 @@
+package buildpack
+
+import (
+	buildclientsetv1 "github.com/openshift/client-go/build/clientset/versioned/typed/build/v1"
+	restclient "k8s.io/client-go/rest"
+
+	buildv1 "github.com/openshift/api/build/v1"
+	imagev1 "github.com/openshift/api/image/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/snowdrop/spring-boot-cloud-devex/pkg/buildpack/types"
+)
+
+// gitBuilderImageStream is the S2I builder image Git-source builds compile
+// against -- the same Java builder the supervisord source-sync strategy's
+// defaultImages import, imported on its own since the Git strategy has no
+// use for the copy-supervisord image.
+var gitBuilderImageStream = []types.Image{
+	*CreateTypeImage(true, "dev-s2i", "latest", "quay.io/snowdrop/spring-boot-s2i", false),
+}
+
+// CreateGitBuildConfig sets up the Git-source build strategy: the dev-s2i
+// builder ImageStream, application.Name's own output ImageStream, and a
+// BuildConfig that builds application.GitURL (at application.GitRef, if
+// set) into it with the S2I strategy. It's a distinct source strategy from
+// the supervisord push/compile sync: there's no local file sync, and the
+// resulting image is rolled out via CreateOrRetrieveDeploymentConfig's
+// ImageChange trigger instead.
+func CreateGitBuildConfig(config *restclient.Config, application types.Application) {
+	CreateImageStreamTemplate(config, application, gitBuilderImageStream)
+
+	imageClient := getImageClient(config)
+	if _, err := imageClient.ImageStreams(application.Namespace).Get(application.Name, metav1.GetOptions{}); apierrors.IsNotFound(err) {
+		_, err := imageClient.ImageStreams(application.Namespace).Create(&imagev1.ImageStream{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: application.Name,
+				Labels: MergeLabels(application.Labels, map[string]string{
+					"app":        application.Name,
+					OdoLabelName: OdoLabelValue,
+				}),
+			},
+		})
+		if err != nil {
+			log.Fatalf("Unable to create output ImageStream '%s': %s", application.Name, err.Error())
+		}
+	} else if err != nil {
+		log.Fatalf("Unable to check for an existing output ImageStream '%s': %s", application.Name, err.Error())
+	}
+
+	buildClient, err := buildclientsetv1.NewForConfig(config)
+	if err != nil {
+		log.Fatalf("Can't get BuildConfig Clientset: %s", err.Error())
+	}
+
+	if _, err := buildClient.BuildConfigs(application.Namespace).Get(application.Name, metav1.GetOptions{}); err == nil {
+		log.Infof("'%s' BuildConfig already exists, skipping", application.Name)
+		return
+	} else if !apierrors.IsNotFound(err) {
+		log.Fatalf("Unable to check for an existing BuildConfig: %s", err.Error())
+	}
+
+	if _, err := buildClient.BuildConfigs(application.Namespace).Create(gitBuildConfig(application)); err != nil {
+		log.Fatalf("Unable to create BuildConfig: %s", err.Error())
+	}
+}
+
+func gitBuildConfig(application types.Application) *buildv1.BuildConfig {
+	return &buildv1.BuildConfig{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: application.Name,
+			Labels: MergeLabels(application.Labels, map[string]string{
+				"app":        application.Name,
+				OdoLabelName: OdoLabelValue,
+			}),
+		},
+		Spec: buildv1.BuildConfigSpec{
+			CommonSpec: buildv1.CommonSpec{
+				Source: buildv1.BuildSource{
+					Type: buildv1.BuildSourceGit,
+					Git: &buildv1.GitBuildSource{
+						URI: application.GitURL,
+						Ref: application.GitRef,
+					},
+				},
+				Strategy: buildv1.BuildStrategy{
+					SourceStrategy: &buildv1.SourceBuildStrategy{
+						From: corev1.ObjectReference{
+							Kind: "ImageStreamTag",
+							Name: "dev-s2i:latest",
+						},
+						Incremental: &application.Incremental,
+					},
+				},
+				Output: buildv1.BuildOutput{
+					To: &corev1.ObjectReference{
+						Kind: "ImageStreamTag",
+						Name: application.Name + ":latest",
+					},
+				},
+			},
+			Triggers: []buildv1.BuildTriggerPolicy{
+				{Type: buildv1.ConfigChangeBuildTriggerType},
+				{Type: buildv1.ImageChangeBuildTriggerType, ImageChange: &buildv1.ImageChangeTrigger{}},
+			},
+		},
+	}
+}
@@ -0,0 +1,103 @@
+package buildpack
+
+import (
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+
+	autoscalingv1 "k8s.io/api/autoscaling/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	restclient "k8s.io/client-go/rest"
+
+	"k8s.io/client-go/kubernetes"
+
+	cfg "github.com/snowdrop/spring-boot-cloud-devex/pkg/common/config"
+
+	"github.com/snowdrop/spring-boot-cloud-devex/pkg/buildpack/types"
+)
+
+// defaultHPATargetCPUPercentage is the target CPU utilization BuildHPA
+// assumes when the MANIFEST's hpa.targetCPUPercentage is left at 0.
+const defaultHPATargetCPUPercentage = 80
+
+// metricsAPIGroupVersion is the API the cluster's metrics server registers,
+// without which a HorizontalPodAutoscaler is created but can never scale:
+// it reads pod CPU usage from here, not from the Kubernetes API directly.
+const metricsAPIGroupVersion = "metrics.k8s.io/v1beta1"
+
+// HPAEnabled reports whether application declares an `hpa:` section worth
+// creating, i.e. gave it a MaxReplicas.
+func HPAEnabled(application types.Application) bool {
+	return application.HPA.MaxReplicas > 0
+}
+
+// BuildHPA returns the HorizontalPodAutoscaler that would be created for
+// application, targeting its DeploymentConfig's scale subresource.
+func BuildHPA(application types.Application) *autoscalingv1.HorizontalPodAutoscaler {
+	minReplicas := application.HPA.MinReplicas
+	if minReplicas <= 0 {
+		minReplicas = 1
+	}
+	targetCPUPercentage := application.HPA.TargetCPUPercentage
+	if targetCPUPercentage <= 0 {
+		targetCPUPercentage = defaultHPATargetCPUPercentage
+	}
+
+	return &autoscalingv1.HorizontalPodAutoscaler{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: application.Name,
+			Labels: MergeLabels(application.Labels, map[string]string{
+				"app":        application.Name,
+				OdoLabelName: OdoLabelValue,
+			}),
+		},
+		Spec: autoscalingv1.HorizontalPodAutoscalerSpec{
+			ScaleTargetRef: autoscalingv1.CrossVersionObjectReference{
+				Kind:       "DeploymentConfig",
+				APIVersion: "apps.openshift.io/v1",
+				Name:       application.Name,
+			},
+			MinReplicas:                    &minReplicas,
+			MaxReplicas:                    application.HPA.MaxReplicas,
+			TargetCPUUtilizationPercentage: &targetCPUPercentage,
+		},
+	}
+}
+
+// CreateHPA creates the HorizontalPodAutoscaler BuildHPA renders for
+// application, or returns the existing one if it's already there. It warns
+// (rather than failing) when the cluster doesn't serve the metrics API the
+// autoscaler depends on, since the HPA object itself is still valid to
+// create ahead of a metrics server being installed later.
+func CreateHPA(restConfig *restclient.Config, clientset *kubernetes.Clientset, application types.Application) (*autoscalingv1.HorizontalPodAutoscaler, error) {
+	if err := cfg.RequireServedGroupVersion(restConfig, metricsAPIGroupVersion, "HorizontalPodAutoscaler"); err != nil {
+		log.Warnf("hpa: %s -- the autoscaler will be created but won't be able to scale until a metrics server is installed", err.Error())
+	}
+
+	hpas := clientset.AutoscalingV1().HorizontalPodAutoscalers(application.Namespace)
+
+	existing, err := hpas.Get(application.Name, metav1.GetOptions{})
+	if err == nil {
+		log.Infof("'%s' HorizontalPodAutoscaler already exists, skipping", application.Name)
+		return existing, nil
+	}
+	if !apierrors.IsNotFound(err) {
+		return nil, errors.Wrapf(err, "unable to retrieve existing HorizontalPodAutoscaler '%s'", application.Name)
+	}
+
+	created, err := hpas.Create(BuildHPA(application))
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to create HorizontalPodAutoscaler '%s'", application.Name)
+	}
+	return created, nil
+}
+
+// DeleteHPA deletes application's HorizontalPodAutoscaler, tolerating one
+// that's already gone.
+func DeleteHPA(clientset *kubernetes.Clientset, application types.Application) {
+	err := clientset.AutoscalingV1().HorizontalPodAutoscalers(application.Namespace).Delete(application.Name, deleteOptions)
+	if err != nil && !apierrors.IsNotFound(err) {
+		log.Fatalf("Unable to delete HorizontalPodAutoscaler: %s", err.Error())
+	}
+}
@@ -0,0 +1,55 @@
+package buildpack_test
+
+import (
+	"testing"
+
+	"github.com/snowdrop/spring-boot-cloud-devex/pkg/buildpack"
+	"github.com/snowdrop/spring-boot-cloud-devex/pkg/buildpack/types"
+)
+
+func TestHPAEnabled(t *testing.T) {
+	if buildpack.HPAEnabled(types.Application{}) {
+		t.Error("HPAEnabled() = true for a zero-value HPA, want false")
+	}
+	if !buildpack.HPAEnabled(types.Application{HPA: types.HPA{MaxReplicas: 3}}) {
+		t.Error("HPAEnabled() = false for MaxReplicas: 3, want true")
+	}
+}
+
+func TestBuildHPADefaultsMinReplicasAndTargetCPU(t *testing.T) {
+	application := types.Application{Name: "my-app", HPA: types.HPA{MaxReplicas: 5}}
+
+	hpa := buildpack.BuildHPA(application)
+
+	if hpa.Spec.ScaleTargetRef.Kind != "DeploymentConfig" || hpa.Spec.ScaleTargetRef.Name != "my-app" {
+		t.Errorf("ScaleTargetRef = %+v, want it pointing at DeploymentConfig 'my-app'", hpa.Spec.ScaleTargetRef)
+	}
+	if hpa.Spec.MaxReplicas != 5 {
+		t.Errorf("MaxReplicas = %d, want 5", hpa.Spec.MaxReplicas)
+	}
+	if hpa.Spec.MinReplicas == nil || *hpa.Spec.MinReplicas != 1 {
+		t.Errorf("MinReplicas = %v, want 1 (default)", hpa.Spec.MinReplicas)
+	}
+	if hpa.Spec.TargetCPUUtilizationPercentage == nil || *hpa.Spec.TargetCPUUtilizationPercentage != 80 {
+		t.Errorf("TargetCPUUtilizationPercentage = %v, want 80 (default)", hpa.Spec.TargetCPUUtilizationPercentage)
+	}
+	if hpa.Labels["app"] != "my-app" || hpa.Labels[buildpack.OdoLabelName] != buildpack.OdoLabelValue {
+		t.Errorf("Labels = %v, want 'app' and discovery label set", hpa.Labels)
+	}
+}
+
+func TestBuildHPAHonorsExplicitSettings(t *testing.T) {
+	application := types.Application{
+		Name: "my-app",
+		HPA:  types.HPA{MinReplicas: 2, MaxReplicas: 5, TargetCPUPercentage: 60},
+	}
+
+	hpa := buildpack.BuildHPA(application)
+
+	if *hpa.Spec.MinReplicas != 2 {
+		t.Errorf("MinReplicas = %d, want 2", *hpa.Spec.MinReplicas)
+	}
+	if *hpa.Spec.TargetCPUUtilizationPercentage != 60 {
+		t.Errorf("TargetCPUUtilizationPercentage = %d, want 60", *hpa.Spec.TargetCPUUtilizationPercentage)
+	}
+}
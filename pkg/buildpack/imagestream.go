@@ -1,18 +1,42 @@
 package buildpack
 
 import (
+	"strings"
+	"time"
+
 	"github.com/ghodss/yaml"
+	"github.com/pkg/errors"
 	log "github.com/sirupsen/logrus"
 
 	imagev1 "github.com/openshift/api/image/v1"
 	imageclientsetv1 "github.com/openshift/client-go/image/clientset/versioned/typed/image/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
 	restclient "k8s.io/client-go/rest"
 
 	"github.com/snowdrop/spring-boot-cloud-devex/pkg/buildpack/types"
-	"github.com/snowdrop/spring-boot-cloud-devex/pkg/common/oc"
-	"strings"
+	cfg "github.com/snowdrop/spring-boot-cloud-devex/pkg/common/config"
 )
 
+// imageStreamImportTimeout bounds CreateImageStreamTemplate's wait for a
+// freshly created ImageStream's import to resolve, matching the ~1-2 minute
+// budget a Docker pull of the S2I/supervisord base images typically needs.
+const imageStreamImportTimeout = 2 * time.Minute
+
+// imageStreamTemplateData is the context rendered into the imagestream
+// template. Commands is resolved the same way as the DC's init container
+// command (Application.StartCommand if set, else derived from BuildTool),
+// so the "cmds" annotation it optionally carries never drifts from what the
+// dev pod actually runs.
+type imageStreamTemplateData struct {
+	Name        string
+	Image       types.Image
+	Commands    string
+	LookupLocal bool
+}
+
 var defaultImages = []types.Image{
 	*CreateTypeImage(true, "dev-s2i", "latest", "quay.io/snowdrop/spring-boot-s2i", false),
 	*CreateTypeImage(true, "copy-supervisord", "latest", "quay.io/snowdrop/supervisord", true),
@@ -22,38 +46,180 @@ func CreateDefaultImageStreams(config *restclient.Config, appConfig types.Applic
 	CreateImageStreamTemplate(config, appConfig, defaultImages)
 }
 
-func CreateImageStreamTemplate(config *restclient.Config, appConfig types.Application, images []types.Image) {
-	imageClient := getImageClient(config)
+// rewriteRegistry replaces repo's registry host (its leading "host/"
+// segment, e.g. "quay.io" in "quay.io/snowdrop/spring-boot-s2i") with
+// registry, for clusters whose Application.Registry mirrors or proxies
+// images internally instead of pulling from the image's default registry.
+func rewriteRegistry(repo string, registry string) string {
+	parts := strings.SplitN(repo, "/", 2)
+	if len(parts) != 2 {
+		return repo
+	}
+	return registry + "/" + parts[1]
+}
+
+// BuildImageStreams returns the ImageStreams that would be created for
+// appConfig's images, without touching the cluster.
+func BuildImageStreams(appConfig types.Application, images []types.Image) []imagev1.ImageStream {
+	commands := appConfig.StartCommand
+	if commands == "" {
+		commands = cfg.DefaultCommands(appConfig.BuildTool)
+	}
 
-	appCfg := appConfig
+	built := make([]imagev1.ImageStream, 0, len(images))
 	for _, img := range images {
 
-		appCfg.Image = img
-
-		// first check that the image stream hasn't already been created
-		if oc.Exists("imagestream", img.Name) {
-			log.Infof("'%s' ImageStream already exists, skipping", img.Name)
-		} else {
-			// Parse ImageStream Template
-			tName := strings.Join([]string{builderPath, "imagestream"}, "/")
-			var b = ParseTemplate(tName, appCfg)
-
-			// Create ImageStream struct using the generated ImageStream string
-			img := imagev1.ImageStream{}
-			errYamlParsing := yaml.Unmarshal(b.Bytes(), &img)
-			if errYamlParsing != nil {
-				panic(errYamlParsing)
+		if appConfig.Arch != "" {
+			repo, err := ImageRepoForArch(img.Name, appConfig.Arch)
+			if err != nil {
+				log.Fatal(err.Error())
 			}
+			img.Repo = repo
+		}
+
+		if appConfig.Registry != "" {
+			img.Repo = rewriteRegistry(img.Repo, appConfig.Registry)
+		}
+
+		data := imageStreamTemplateData{Name: appConfig.Name, Image: img, Commands: commands, LookupLocal: appConfig.ImageLookupLocal}
+
+		// Parse ImageStream Template
+		tName := strings.Join([]string{builderPath, "imagestream"}, "/")
+		var b = ParseTemplate(tName, data)
+
+		// Create ImageStream struct using the generated ImageStream string
+		desired := imagev1.ImageStream{}
+		errYamlParsing := yaml.Unmarshal(b.Bytes(), &desired)
+		if errYamlParsing != nil {
+			panic(errYamlParsing)
+		}
+
+		built = append(built, desired)
+	}
+	return built
+}
+
+func CreateImageStreamTemplate(restConfig *restclient.Config, appConfig types.Application, images []types.Image) {
+	imageClient := getImageClient(restConfig)
+	streams := imageClient.ImageStreams(appConfig.Namespace)
+
+	built := BuildImageStreams(appConfig, images)
+	for i, desired := range built {
+		img := images[i]
+
+		if _, err := streams.Get(desired.Name, metav1.GetOptions{}); err == nil {
+			reconcileImageStream(imageClient, appConfig.Namespace, &desired)
+			continue
+		} else if !apierrors.IsNotFound(err) {
+			log.Fatalf("Unable to check for an existing ImageStream: %s", err.Error())
+		}
+
+		_, errImages := streams.Create(&desired)
+		if errImages != nil {
+			log.Fatalf("Unable to create ImageStream: %s", errImages.Error())
+		}
 
-			_, errImages := imageClient.ImageStreams(appConfig.Namespace).Create(&img)
-			if errImages != nil {
-				log.Fatalf("Unable to create ImageStream: %s", errImages.Error())
+		if img.DockerImage {
+			log.Infof("Waiting for '%s:%s' image import to complete", img.Name, img.Tag)
+			if err := WaitForImageStreamImport(restConfig, appConfig.Namespace, img.Name, img.Tag, imageStreamImportTimeout); err != nil {
+				log.Fatalf("ImageStream import failed: %s", err.Error())
 			}
 		}
 	}
 }
 
+// reconcileImageStream updates an existing ImageStream's tags to match
+// desired when the declared Docker image reference has changed, so changing
+// the supervisord/S2I image repo or tag and re-running `sd init` actually
+// triggers a re-import instead of being silently skipped.
+func reconcileImageStream(imageClient *imageclientsetv1.ImageV1Client, namespace string, desired *imagev1.ImageStream) {
+	existing, err := imageClient.ImageStreams(namespace).Get(desired.Name, metav1.GetOptions{})
+	if err != nil {
+		log.Fatalf("Unable to retrieve ImageStream '%s': %s", desired.Name, err.Error())
+	}
+
+	changed := false
+	for _, desiredTag := range desired.Spec.Tags {
+		found := false
+		for i, existingTag := range existing.Spec.Tags {
+			if existingTag.Name != desiredTag.Name {
+				continue
+			}
+			found = true
+			if existingTag.From == nil || desiredTag.From == nil || existingTag.From.Name != desiredTag.From.Name {
+				existing.Spec.Tags[i].From = desiredTag.From
+				changed = true
+			}
+		}
+		if !found {
+			existing.Spec.Tags = append(existing.Spec.Tags, desiredTag)
+			changed = true
+		}
+	}
+
+	if !changed {
+		log.Infof("'%s' ImageStream already up to date, skipping", desired.Name)
+		return
+	}
+
+	if _, err := imageClient.ImageStreams(namespace).Update(existing); err != nil {
+		log.Fatalf("Unable to update ImageStream '%s': %s", desired.Name, err.Error())
+	}
+	log.Infof("Updated '%s' ImageStream's tag(s) to trigger a re-import", desired.Name)
+}
+
+// WaitForImageStreamImport polls streamName's ImageStream until tagName's
+// import either succeeds (a TagEvent recorded at or after the tag's current
+// spec generation) or fails (an ImportSuccess=False condition at or after
+// that generation, e.g. unauthorized pulling the base image), or until
+// timeout elapses. Without this, CreateImageStreamTemplate could return
+// before the asynchronous import resolves, letting the DC start rolling out
+// against a tag that isn't there yet.
+func WaitForImageStreamImport(restConfig *restclient.Config, namespace string, streamName string, tagName string, timeout time.Duration) error {
+	imageClient := getImageClient(restConfig)
+
+	err := wait.PollImmediate(2*time.Second, timeout, func() (bool, error) {
+		stream, err := imageClient.ImageStreams(namespace).Get(streamName, metav1.GetOptions{})
+		if err != nil {
+			return false, err
+		}
+
+		var specGeneration int64
+		for _, tag := range stream.Spec.Tags {
+			if tag.Name == tagName && tag.Generation != nil {
+				specGeneration = *tag.Generation
+			}
+		}
+
+		for _, tagStatus := range stream.Status.Tags {
+			if tagStatus.Tag != tagName {
+				continue
+			}
+			for _, condition := range tagStatus.Conditions {
+				if condition.Type == imagev1.ImportSuccess && condition.Status == corev1.ConditionFalse && condition.Generation >= specGeneration {
+					return false, errors.Errorf("import of '%s:%s' failed: %s", streamName, tagName, condition.Message)
+				}
+			}
+			for _, item := range tagStatus.Items {
+				if item.Generation >= specGeneration {
+					return true, nil
+				}
+			}
+		}
+		return false, nil
+	})
+
+	if err == wait.ErrWaitTimeout {
+		return errors.Errorf("timed out after %s waiting for '%s:%s' to import", timeout, streamName, tagName)
+	}
+	return err
+}
+
 func getImageClient(config *restclient.Config) *imageclientsetv1.ImageV1Client {
+	if err := cfg.RequireServedGroupVersion(config, imagev1.SchemeGroupVersion.String(), "ImageStream"); err != nil {
+		log.Fatal(err.Error())
+	}
+
 	imageClient, err := imageclientsetv1.NewForConfig(config)
 	if err != nil {
 		log.Fatal("Couldn't get ImageV1Client: %s", err)
@@ -62,18 +228,73 @@ func getImageClient(config *restclient.Config) *imageclientsetv1.ImageV1Client {
 }
 
 func DeleteDefaultImageStreams(config *restclient.Config, appConfig types.Application) {
+	client := getImageClient(config)
 	for _, img := range defaultImages {
-		// first check that the image stream hasn't already been created
-		if oc.Exists("imagestream", img.Name) {
-			client := getImageClient(config)
-			err := client.ImageStreams(appConfig.Namespace).Delete(img.Name, deleteOptions)
-			if err != nil {
-				log.Fatalf("Unable to delete ImageStream: %s", img.Name)
-			}
+		err := client.ImageStreams(appConfig.Namespace).Delete(img.Name, deleteOptions)
+		if err != nil && !apierrors.IsNotFound(err) {
+			log.Fatalf("Unable to delete ImageStream: %s", img.Name)
 		}
 	}
 }
 
+// TagImage retags/promotes the "fromImageStreamTag" (e.g. "dev-s2i:latest")
+// to "toName:toTag" within the same namespace, creating the new
+// ImageStreamTag if it doesn't already exist.
+func TagImage(config *restclient.Config, namespace string, fromImageStreamTag string, toName string, toTag string) {
+	imageClient := getImageClient(config)
+
+	ist := &imagev1.ImageStreamTag{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: toName + ":" + toTag,
+		},
+		Tag: &imagev1.TagReference{
+			From: &corev1.ObjectReference{
+				Kind: "ImageStreamTag",
+				Name: fromImageStreamTag,
+			},
+		},
+	}
+
+	_, err := imageClient.ImageStreamTags(namespace).Create(ist)
+	if err != nil {
+		log.Fatalf("Unable to tag image: %s", err.Error())
+	}
+}
+
+// TagExternalImage imports the external Docker image reference imageRef
+// (e.g. produced by an out-of-band CI build) as toName:toTag, creating the
+// ImageStreamTag if it doesn't exist yet or retargeting it otherwise. Unlike
+// TagImage, which promotes one ImageStreamTag to another within the same
+// cluster, imageRef here is a plain pull spec resolved from outside.
+func TagExternalImage(config *restclient.Config, namespace string, imageRef string, toName string, toTag string) {
+	imageClient := getImageClient(config)
+	tagName := toName + ":" + toTag
+
+	tagRef := &imagev1.TagReference{
+		From: &corev1.ObjectReference{
+			Kind: "DockerImage",
+			Name: imageRef,
+		},
+		ReferencePolicy: imagev1.TagReferencePolicy{Type: imagev1.LocalTagReferencePolicy},
+	}
+
+	if existing, err := imageClient.ImageStreamTags(namespace).Get(tagName, metav1.GetOptions{}); err == nil {
+		existing.Tag = tagRef
+		if _, err := imageClient.ImageStreamTags(namespace).Update(existing); err != nil {
+			log.Fatalf("Unable to retag '%s' to '%s': %s", tagName, imageRef, err.Error())
+		}
+		return
+	}
+
+	ist := &imagev1.ImageStreamTag{
+		ObjectMeta: metav1.ObjectMeta{Name: tagName},
+		Tag:        tagRef,
+	}
+	if _, err := imageClient.ImageStreamTags(namespace).Create(ist); err != nil {
+		log.Fatalf("Unable to tag '%s' as '%s': %s", imageRef, tagName, err.Error())
+	}
+}
+
 func CreateTypeImage(dockerImage bool, name string, tag string, repo string, annotationCmd bool) *types.Image {
 	return &types.Image{
 		DockerImage:    dockerImage,
@@ -0,0 +1,59 @@
+package buildpack
+
+import (
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	restclient "k8s.io/client-go/rest"
+
+	routeclientsetv1 "github.com/openshift/client-go/route/clientset/versioned/typed/route/v1"
+)
+
+// InternalRegistryNamespace and InternalRegistryRouteName are where an
+// OpenShift administrator exposes the cluster's internal image registry
+// externally, when they've chosen to (it's disabled by default).
+const InternalRegistryNamespace = "openshift-image-registry"
+const InternalRegistryRouteName = "default-route"
+
+// InternalRegistryServiceHost is the internal registry's in-cluster DNS
+// name, reachable only from inside the cluster -- DiscoverInternalRegistryHost
+// falls back to it whenever the registry isn't exposed via a Route.
+const InternalRegistryServiceHost = "image-registry.openshift-image-registry.svc:5000"
+
+// DiscoverInternalRegistryHost returns the OpenShift internal image
+// registry's externally-reachable host, read from its "default-route"
+// Route in the openshift-image-registry namespace, so pushing/pulling from
+// outside the cluster works. It falls back to InternalRegistryServiceHost
+// when that Route doesn't exist, hasn't been given a host yet, or the
+// cluster doesn't serve the Route API at all (vanilla Kubernetes) --
+// none of which are treated as errors, since most clusters simply don't
+// expose the registry externally.
+func DiscoverInternalRegistryHost(config *restclient.Config) string {
+	routeV1Client, err := routeclientsetv1.NewForConfig(config)
+	if err != nil {
+		log.Debugf("internal registry: unable to create Route client: %s", err.Error())
+		return InternalRegistryServiceHost
+	}
+
+	route, err := routeV1Client.Routes(InternalRegistryNamespace).Get(InternalRegistryRouteName, metav1.GetOptions{})
+	if err != nil || route.Spec.Host == "" {
+		log.Debugf("internal registry: '%s' Route not found in '%s', using the in-cluster service name", InternalRegistryRouteName, InternalRegistryNamespace)
+		return InternalRegistryServiceHost
+	}
+
+	return route.Spec.Host
+}
+
+// RewriteInternalRegistryHost replaces image's leading
+// InternalRegistryServiceHost segment with host, for a devImage that
+// references the internal registry by its in-cluster service name but
+// needs to be pulled from outside the cluster. Anything else is returned
+// unchanged.
+func RewriteInternalRegistryHost(image string, host string) string {
+	if host == InternalRegistryServiceHost || !strings.HasPrefix(image, InternalRegistryServiceHost+"/") {
+		return image
+	}
+	return host + strings.TrimPrefix(image, InternalRegistryServiceHost)
+}
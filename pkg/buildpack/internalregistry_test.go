@@ -0,0 +1,45 @@
+package buildpack_test
+
+import (
+	"testing"
+
+	"github.com/snowdrop/spring-boot-cloud-devex/pkg/buildpack"
+)
+
+func TestRewriteInternalRegistryHost(t *testing.T) {
+	exposedHost := "default-route-openshift-image-registry.apps.example.com"
+
+	cases := []struct {
+		name  string
+		image string
+		host  string
+		want  string
+	}{
+		{
+			"rewrites an in-cluster reference to the exposed Route host",
+			buildpack.InternalRegistryServiceHost + "/my-ns/my-app:latest",
+			exposedHost,
+			exposedHost + "/my-ns/my-app:latest",
+		},
+		{
+			"leaves other registries untouched",
+			"quay.io/snowdrop/spring-boot-s2i:latest",
+			exposedHost,
+			"quay.io/snowdrop/spring-boot-s2i:latest",
+		},
+		{
+			"leaves the image untouched when the registry isn't exposed",
+			buildpack.InternalRegistryServiceHost + "/my-ns/my-app:latest",
+			buildpack.InternalRegistryServiceHost,
+			buildpack.InternalRegistryServiceHost + "/my-ns/my-app:latest",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := buildpack.RewriteInternalRegistryHost(c.image, c.host); got != c.want {
+				t.Errorf("RewriteInternalRegistryHost() = %q, want %q", got, c.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,18 @@
+package buildpack
+
+// MergeLabels returns a new map containing base's entries overlaid with
+// extra's. It's used to fold MANIFEST `labels:` and `sd init --label`
+// values into the labels applied to generated resources; both are
+// validated ahead of time to reject OdoLabelName, so they never override
+// the discovery label Setup/WaitAndGetPod rely on, which callers pass in
+// extra.
+func MergeLabels(base map[string]string, extra map[string]string) map[string]string {
+	merged := make(map[string]string, len(base)+len(extra))
+	for k, v := range base {
+		merged[k] = v
+	}
+	for k, v := range extra {
+		merged[k] = v
+	}
+	return merged
+}
@@ -3,34 +3,152 @@ package buildpack
 import (
 	log "github.com/sirupsen/logrus"
 	"io/ioutil"
+	"net"
+	"strings"
 
 	"encoding/json"
 	"github.com/ghodss/yaml"
+	"github.com/pkg/errors"
 	"github.com/snowdrop/spring-boot-cloud-devex/pkg/buildpack/types"
+	"k8s.io/apimachinery/pkg/util/validation"
 	"os"
+	"path/filepath"
 )
 
-func ParseManifest(manifestPath string) types.Application {
+// ResolveManifestPath returns the path ParseManifest should read: override
+// if `--manifest` was given, or cwd's "MANIFEST" otherwise. A missing
+// default path is fine -- ParseManifest treats it as "no MANIFEST, use
+// defaults" -- but an explicit override that doesn't exist or can't be
+// read is almost certainly a typo, so it's rejected here with the resolved
+// path rather than surfacing a confusing "using default values" later.
+func ResolveManifestPath(cwd string, override string) (string, error) {
+	if override == "" {
+		return filepath.Join(cwd, "MANIFEST"), nil
+	}
+
+	resolved, err := filepath.Abs(override)
+	if err != nil {
+		return "", errors.Wrapf(err, "--manifest: unable to resolve '%s'", override)
+	}
+
+	f, err := os.Open(resolved)
+	if err != nil {
+		return "", errors.Wrapf(err, "--manifest: '%s' does not exist or is not readable", resolved)
+	}
+	f.Close()
+
+	return resolved, nil
+}
+
+// ParseManifest reads manifestPath (following any `extends` chain) into an
+// Application, starting from types.NewApplication's defaults. Each path in
+// overlays -- `--manifest` given more than once -- is loaded the same way
+// (its own `extends` chain included) and deep-merged on top, in order:
+// nested maps merge key-by-key, everything else (scalars, lists) is
+// replaced outright, the same semantics `extends` and profiles already use.
+// This lets a team pass `--manifest base.yaml --manifest env.yaml
+// --manifest local.yaml` to layer a base, an env overlay, and a local
+// developer overlay without repeating shared fields. When profile is
+// non-empty, it selects the final merged document's `profiles: <name>:
+// {...}` entry and merges that in last, so a profile can still override
+// namespace, replicas, resources, env, or any other field regardless of
+// which file declared it. ParseManifest exits the process with a clear
+// error if profile is set but not declared.
+func ParseManifest(manifestPath string, profile string, overlays ...string) types.Application {
 	log.Debugf("Parsing Application Config at %s", manifestPath)
 
 	// Create an Application with default values
 	appConfig := types.NewApplication()
 
-	// if we have a manifest file, use it to replace default values
+	// if we have a manifest file, use it (and whatever it "extends") to
+	// replace default values
 	if _, err := os.Stat(manifestPath); err == nil {
-		source, err := ioutil.ReadFile(manifestPath)
-		if err != nil {
-			panic(err)
+		raw := loadManifest(manifestPath, map[string]bool{})
+
+		for _, overlay := range overlays {
+			raw = mergeManifests(raw, loadManifest(overlay, map[string]bool{}))
 		}
 
-		err = yaml.Unmarshal(source, &appConfig)
+		raw = selectProfile(raw, profile)
+
+		merged, err := yaml.Marshal(raw)
 		if err != nil {
 			log.Fatal(err)
 		}
+
+		if err := yaml.Unmarshal(merged, &appConfig); err != nil {
+			log.Fatal(err)
+		}
 	} else {
 		log.Infof("No MANIFEST file detected, using default values")
 	}
 
+	// Resolve "auto" to the build tool detected from the project's files
+	appConfig.BuildTool = DetectBuildTool(filepath.Dir(manifestPath), appConfig.BuildTool)
+
+	// When the MANIFEST leaves Port at its default, try to detect
+	// server.port from the project's Spring Boot configuration instead of
+	// silently deploying a Route that can't reach the application.
+	appConfig.Port = DetectServerPort(filepath.Dir(manifestPath), appConfig.Port)
+
+	// Resolve "auto" to the probe preset detected from the project's
+	// declared Spring Boot version.
+	appConfig.ProbePreset = ResolveProbePreset(filepath.Dir(manifestPath), appConfig.ProbePreset)
+
+	for _, hostAlias := range appConfig.HostAliases {
+		if net.ParseIP(hostAlias.IP) == nil {
+			log.Fatalf("hostAliases: '%s' is not a valid IP address", hostAlias.IP)
+		}
+	}
+
+	if _, reserved := appConfig.Labels[OdoLabelName]; reserved {
+		log.Fatalf("labels: '%s' is reserved for pod discovery and cannot be set in the MANIFEST", OdoLabelName)
+	}
+
+	if appConfig.SupervisordPort == appConfig.Port || appConfig.SupervisordPort == appConfig.ManagementPort {
+		log.Fatalf("supervisordPort: %d collides with the application's port or management port", appConfig.SupervisordPort)
+	}
+
+	if appConfig.Replicas < 0 {
+		log.Fatalf("replicas: %d must be >= 0", appConfig.Replicas)
+	}
+	if appConfig.Replicas > 1 {
+		log.Warn("replicas > 1: the m2-data PVC is ReadWriteOnce and can only be mounted by one pod at a time, so only one replica can actually schedule unless --no-cache is used")
+	}
+	if appConfig.HPA.MaxReplicas > 1 {
+		log.Warn("hpa.maxReplicas > 1: the m2-data PVC is ReadWriteOnce and can only be mounted by one pod at a time, so scaling beyond 1 replica will leave extra pods stuck Pending unless --no-cache is used")
+	}
+
+	if appConfig.GitURL != "" {
+		// The Git-source build strategy never runs supervisord, regardless of
+		// what the MANIFEST's mountSource says, so push/compile/run's
+		// MountSource check reliably refuses to run against it.
+		appConfig.MountSource = false
+	}
+
+	if !appConfig.MountSource && appConfig.DevImage == "" && appConfig.GitURL == "" {
+		log.Fatal("mountSource: false requires devImage or gitUrl to be set, since there's no supervisord/S2I wiring left to build or run the application with")
+	}
+
+	for _, arg := range appConfig.Lifecycle.PreStop {
+		if strings.TrimSpace(arg) == "" {
+			log.Fatal("lifecycle.preStop: command array must not contain empty strings")
+		}
+	}
+
+	if appConfig.ContainerName != "" {
+		if errs := validation.IsDNS1123Label(appConfig.ContainerName); len(errs) > 0 {
+			log.Fatalf("containerName: '%s' is not a valid RFC 1123 label: %s", appConfig.ContainerName, strings.Join(errs, "; "))
+		}
+	}
+
+	if appConfig.DevImage != "" {
+		if strings.ContainsAny(appConfig.DevImage, " \t\n") {
+			log.Fatalf("devImage: '%s' is not a valid image reference", appConfig.DevImage)
+		}
+		log.Warn("devImage is set: skipping the S2I ImageStreams and using the image as-is. Commands that rsh into the pod (compile, push, run, exec) assume supervisord is present at /var/lib/supervisord")
+	}
+
 	log.Infof("Application configured")
 
 	if log.GetLevel() == log.DebugLevel {
@@ -42,3 +160,90 @@ func ParseManifest(manifestPath string) types.Application {
 
 	return appConfig
 }
+
+// loadManifest reads manifestPath and, if it declares a top-level `extends:
+// <path>` key (resolved relative to manifestPath's directory, or absolute),
+// recursively loads and deep-merges it underneath the current file's
+// content before returning. Maps are merged key-by-key; any other value
+// (including lists) in the extending file replaces the base's value
+// outright. visited tracks the absolute paths already loaded in this chain
+// so an extends cycle is reported instead of recursing forever.
+func loadManifest(manifestPath string, visited map[string]bool) map[string]interface{} {
+	absPath, err := filepath.Abs(manifestPath)
+	if err != nil {
+		log.Fatalf("Unable to resolve MANIFEST path '%s': %s", manifestPath, err.Error())
+	}
+	if visited[absPath] {
+		log.Fatalf("MANIFEST 'extends' cycle detected at '%s'", manifestPath)
+	}
+	visited[absPath] = true
+
+	source, err := ioutil.ReadFile(manifestPath)
+	if err != nil {
+		panic(err)
+	}
+
+	var raw map[string]interface{}
+	if err := yaml.Unmarshal(source, &raw); err != nil {
+		log.Fatal(err)
+	}
+
+	extends, _ := raw["extends"].(string)
+	if extends == "" {
+		return raw
+	}
+	delete(raw, "extends")
+
+	basePath := extends
+	if !filepath.IsAbs(basePath) {
+		basePath = filepath.Join(filepath.Dir(manifestPath), basePath)
+	}
+
+	return mergeManifests(loadManifest(basePath, visited), raw)
+}
+
+// selectProfile strips raw's top-level `profiles:` map, if any -- Application
+// has no field for it -- and, when profile is non-empty, deep-merges that
+// profile's entry over raw via mergeManifests. Exits the process if profile
+// is set but raw declares no such profile, listing the ones that are.
+func selectProfile(raw map[string]interface{}, profile string) map[string]interface{} {
+	profiles, _ := raw["profiles"].(map[string]interface{})
+	delete(raw, "profiles")
+
+	if profile == "" {
+		return raw
+	}
+
+	selected, ok := profiles[profile].(map[string]interface{})
+	if !ok {
+		names := make([]string, 0, len(profiles))
+		for name := range profiles {
+			names = append(names, name)
+		}
+		log.Fatalf("manifest-profile: '%s' is not declared in the MANIFEST's profiles (known: %s)", profile, strings.Join(names, ", "))
+	}
+
+	return mergeManifests(raw, selected)
+}
+
+// mergeManifests deep-merges override onto base: nested maps are merged
+// key-by-key, while any other value -- scalars and lists alike -- in
+// override simply replaces base's, since there's no single obviously
+// correct way to append two lists (e.g. two `env` entries with the same
+// Name) that wouldn't surprise someone debugging an inherited MANIFEST.
+func mergeManifests(base map[string]interface{}, override map[string]interface{}) map[string]interface{} {
+	merged := make(map[string]interface{}, len(base)+len(override))
+	for k, v := range base {
+		merged[k] = v
+	}
+	for k, v := range override {
+		if baseMap, ok := merged[k].(map[string]interface{}); ok {
+			if overrideMap, ok := v.(map[string]interface{}); ok {
+				merged[k] = mergeManifests(baseMap, overrideMap)
+				continue
+			}
+		}
+		merged[k] = v
+	}
+	return merged
+}
@@ -0,0 +1,152 @@
+package buildpack_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/snowdrop/spring-boot-cloud-devex/pkg/buildpack"
+)
+
+func writeManifest(t *testing.T, dir string, name string, content string) string {
+	path := filepath.Join(dir, name)
+	if err := ioutil.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestParseManifestExtends(t *testing.T) {
+	dir, err := ioutil.TempDir("", "manifest-extends-test-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	writeManifest(t, dir, "base.yaml", "name: base-app\ncpu: 200m\nmemory: 256Mi\n")
+	childPath := writeManifest(t, dir, "MANIFEST", "extends: base.yaml\nname: child-app\n")
+
+	app := buildpack.ParseManifest(childPath, "")
+
+	if app.Name != "child-app" {
+		t.Errorf("Name = %q, want %q (override should win)", app.Name, "child-app")
+	}
+	if app.Cpu != "200m" {
+		t.Errorf("Cpu = %q, want %q (inherited from base)", app.Cpu, "200m")
+	}
+}
+
+func TestParseManifestProfile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "manifest-profile-test-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	manifestPath := writeManifest(t, dir, "MANIFEST", ""+
+		"name: my-app\n"+
+		"namespace: dev-ns\n"+
+		"replicas: 1\n"+
+		"profiles:\n"+
+		"  prod:\n"+
+		"    namespace: prod-ns\n"+
+		"    replicas: 3\n")
+
+	app := buildpack.ParseManifest(manifestPath, "prod")
+
+	if app.Namespace != "prod-ns" {
+		t.Errorf("Namespace = %q, want %q (profile override should win)", app.Namespace, "prod-ns")
+	}
+	if app.Replicas != 3 {
+		t.Errorf("Replica = %d, want %d (profile override should win)", app.Replicas, 3)
+	}
+	if app.Name != "my-app" {
+		t.Errorf("Name = %q, want %q (unset by profile, should keep base value)", app.Name, "my-app")
+	}
+}
+
+func TestParseManifestMultipleOverlaysDeepMerge(t *testing.T) {
+	dir, err := ioutil.TempDir("", "manifest-overlays-test-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	basePath := writeManifest(t, dir, "base.yaml", ""+
+		"name: base-app\n"+
+		"namespace: dev-ns\n"+
+		"cpu: 200m\n"+
+		"env:\n"+
+		"  - name: FOO\n"+
+		"    value: base\n"+
+		"labels:\n"+
+		"  team: payments\n")
+
+	envPath := writeManifest(t, dir, "env.yaml", ""+
+		"namespace: staging-ns\n"+
+		"env:\n"+
+		"  - name: BAR\n"+
+		"    value: staging\n")
+
+	localPath := writeManifest(t, dir, "local.yaml", ""+
+		"replicas: 3\n"+
+		"labels:\n"+
+		"  owner: alice\n")
+
+	app := buildpack.ParseManifest(basePath, "", envPath, localPath)
+
+	if app.Name != "base-app" {
+		t.Errorf("Name = %q, want %q (only base.yaml declares it)", app.Name, "base-app")
+	}
+	if app.Namespace != "staging-ns" {
+		t.Errorf("Namespace = %q, want %q (scalar: later overlay replaces earlier)", app.Namespace, "staging-ns")
+	}
+	if app.Replicas != 3 {
+		t.Errorf("Replicas = %d, want %d (from local.yaml)", app.Replicas, 3)
+	}
+	if len(app.Env) != 1 || app.Env[0].Name != "BAR" {
+		t.Errorf("Env = %+v, want a single BAR entry (list: env.yaml replaces base.yaml's list outright, not appended)", app.Env)
+	}
+	if app.Labels["team"] != "payments" {
+		t.Errorf("Labels[team] = %q, want %q (map: merged key-by-key, base.yaml's key survives)", app.Labels["team"], "payments")
+	}
+	if app.Labels["owner"] != "alice" {
+		t.Errorf("Labels[owner] = %q, want %q (map: local.yaml's key added)", app.Labels["owner"], "alice")
+	}
+}
+
+func TestResolveManifestPathDefault(t *testing.T) {
+	path, err := buildpack.ResolveManifestPath("/some/project", "")
+	if err != nil {
+		t.Fatalf("ResolveManifestPath() returned an unexpected error: %s", err.Error())
+	}
+	if want := filepath.Join("/some/project", "MANIFEST"); path != want {
+		t.Errorf("ResolveManifestPath() = %q, want %q", path, want)
+	}
+}
+
+func TestResolveManifestPathOverride(t *testing.T) {
+	dir, err := ioutil.TempDir("", "manifest-override-test-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	manifestPath := writeManifest(t, dir, "custom.yaml", "name: my-app\n")
+
+	path, err := buildpack.ResolveManifestPath("/some/project", manifestPath)
+	if err != nil {
+		t.Fatalf("ResolveManifestPath() returned an unexpected error: %s", err.Error())
+	}
+	if path != manifestPath {
+		t.Errorf("ResolveManifestPath() = %q, want %q", path, manifestPath)
+	}
+}
+
+func TestResolveManifestPathOverrideMissing(t *testing.T) {
+	_, err := buildpack.ResolveManifestPath("/some/project", "/does/not/exist.yaml")
+	if err == nil {
+		t.Fatal("ResolveManifestPath() expected an error for a missing override, got nil")
+	}
+}
@@ -0,0 +1,72 @@
+package buildpack
+
+import (
+	"io/ioutil"
+
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/snowdrop/spring-boot-cloud-devex/pkg/buildpack/types"
+)
+
+// mavenSettingsConfigMapName is the ConfigMap BuildDeploymentConfig mounts
+// at mavenSettingsMountPath whenever Application.MavenSettings is set.
+const mavenSettingsConfigMapName = "maven-settings"
+
+// mavenSettingsMountPath is where the s2i Java builder looks for a custom
+// settings.xml, so mounting it there takes effect without any further
+// build configuration.
+const mavenSettingsMountPath = "/tmp/artifacts/configuration"
+
+// CreateMavenSettingsConfigMap reads application.MavenSettings from disk and
+// creates (or updates, if one already exists) the "maven-settings" ConfigMap
+// that BuildDeploymentConfig mounts into the dev pod.
+func CreateMavenSettingsConfigMap(clientset *kubernetes.Clientset, application types.Application) error {
+	content, err := ioutil.ReadFile(application.MavenSettings)
+	if err != nil {
+		return errors.Wrapf(err, "unable to read '%s'", application.MavenSettings)
+	}
+
+	configMaps := clientset.CoreV1().ConfigMaps(application.Namespace)
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: mavenSettingsConfigMapName,
+			Labels: map[string]string{
+				"app": application.Name,
+			},
+		},
+		Data: map[string]string{
+			"settings.xml": string(content),
+		},
+	}
+
+	if _, err := configMaps.Get(mavenSettingsConfigMapName, metav1.GetOptions{}); err == nil {
+		if _, err := configMaps.Update(cm); err != nil {
+			return errors.Wrap(err, "unable to update maven-settings ConfigMap")
+		}
+		log.Infof("'%s' ConfigMap updated", mavenSettingsConfigMapName)
+		return nil
+	} else if !apierrors.IsNotFound(err) {
+		return errors.Wrap(err, "unable to check for an existing maven-settings ConfigMap")
+	}
+
+	if _, err := configMaps.Create(cm); err != nil {
+		return errors.Wrap(err, "unable to create maven-settings ConfigMap")
+	}
+	log.Infof("'%s' ConfigMap created", mavenSettingsConfigMapName)
+	return nil
+}
+
+// DeleteMavenSettingsConfigMap deletes the "maven-settings" ConfigMap, if it
+// exists.
+func DeleteMavenSettingsConfigMap(clientset *kubernetes.Clientset, application types.Application) {
+	err := clientset.CoreV1().ConfigMaps(application.Namespace).Delete(mavenSettingsConfigMapName, deleteOptions)
+	if err != nil && !apierrors.IsNotFound(err) {
+		log.Fatal(err.Error())
+	}
+}
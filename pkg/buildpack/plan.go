@@ -0,0 +1,109 @@
+package buildpack
+
+import (
+	"fmt"
+	"reflect"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	restclient "k8s.io/client-go/rest"
+
+	"github.com/snowdrop/spring-boot-cloud-devex/pkg/buildpack/types"
+)
+
+// PlanStep is one line of a Plan: the action `sd init` would take against a
+// single resource, and why, without actually taking it.
+type PlanStep struct {
+	Action   string // "CREATE", "UPDATE", "SKIP", or "VALIDATE"
+	Resource string
+	Reason   string
+}
+
+func (step PlanStep) String() string {
+	if step.Reason == "" {
+		return fmt.Sprintf("%s %s", step.Action, step.Resource)
+	}
+	return fmt.Sprintf("%s %s (%s)", step.Action, step.Resource, step.Reason)
+}
+
+// BuildPlan mirrors finishSetupAndSetApplicationName's CREATE/SKIP decisions
+// and diff.go's live-vs-desired comparisons to describe, without creating or
+// updating anything, what `sd init` would do for application. It assumes
+// application.Name is already resolved (the interactive prompt step in
+// finishSetupAndSetApplicationName has no side-effect-free equivalent, so
+// `--show-plan` skips it rather than simulating it). An UPDATE step marks a
+// live DeploymentConfig, Service or Route whose spec differs from the
+// desired one; `sd init` itself leaves such an existing resource unchanged.
+func BuildPlan(config *restclient.Config, clientset kubernetes.Interface, application types.Application, commands string, noCache bool) []PlanStep {
+	var plan []PlanStep
+
+	if application.DevImage != "" {
+		plan = append(plan, PlanStep{"SKIP", "ImageStreams", "devImage is set"})
+	} else {
+		imageClient := getImageClient(config)
+		allExist := true
+		for _, img := range defaultImages {
+			if _, err := imageClient.ImageStreams(application.Namespace).Get(img.Name, metav1.GetOptions{}); err != nil {
+				allExist = false
+				break
+			}
+		}
+		if allExist {
+			plan = append(plan, PlanStep{"SKIP", "ImageStreams", "exist"})
+		} else {
+			plan = append(plan, PlanStep{"CREATE", "ImageStreams", ""})
+		}
+	}
+
+	_, pvcErr := clientset.CoreV1().PersistentVolumeClaims(application.Namespace).Get(pvcNameFor(application), metav1.GetOptions{})
+	if application.PVCName != "" {
+		// Setup() calls ValidatePVC, not CreatePVC, against an explicit
+		// --use-pvc: it's expected to already exist and fails outright if
+		// it doesn't, rather than creating or skipping it.
+		if pvcErr == nil {
+			plan = append(plan, PlanStep{"VALIDATE", "PVC", "--use-pvc"})
+		} else {
+			plan = append(plan, PlanStep{"VALIDATE", "PVC", "--use-pvc, will fail: not found"})
+		}
+	} else if noCache {
+		plan = append(plan, PlanStep{"SKIP", "PVC", "--no-cache"})
+	} else if pvcErr == nil {
+		plan = append(plan, PlanStep{"SKIP", "PVC", "exists"})
+	} else {
+		plan = append(plan, PlanStep{"CREATE", "PVC", ""})
+	}
+
+	if _, err := getAppsClient(config).DeploymentConfigs(application.Namespace).Get(application.Name, metav1.GetOptions{}); err != nil {
+		plan = append(plan, PlanStep{"CREATE", "DeploymentConfig", ""})
+	} else if DiffDeploymentConfig(config, application, commands, noCache) != "" {
+		plan = append(plan, PlanStep{"UPDATE", "DeploymentConfig", "spec changed"})
+	} else {
+		plan = append(plan, PlanStep{"SKIP", "DeploymentConfig", "up to date"})
+	}
+
+	if live, err := clientset.CoreV1().Services(application.Namespace).Get(application.Name, metav1.GetOptions{}); err != nil {
+		plan = append(plan, PlanStep{"CREATE", "Service", ""})
+	} else if !reflect.DeepEqual(BuildService(application).Spec, live.Spec) {
+		plan = append(plan, PlanStep{"UPDATE", "Service", "spec changed"})
+	} else {
+		plan = append(plan, PlanStep{"SKIP", "Service", "up to date"})
+	}
+
+	routeV1Client := getClient(config)
+	for _, route := range applicationRoutes(application) {
+		live, err := routeV1Client.Routes(application.Namespace).Get(route.Name, metav1.GetOptions{})
+		if err != nil {
+			plan = append(plan, PlanStep{"CREATE", "Route " + route.Name, ""})
+		} else if !reflect.DeepEqual(BuildRoute(application, route).Spec, live.Spec) {
+			plan = append(plan, PlanStep{"UPDATE", "Route " + route.Name, "spec changed"})
+		} else {
+			plan = append(plan, PlanStep{"SKIP", "Route " + route.Name, "up to date"})
+		}
+	}
+
+	if len(application.ExtraManifests) > 0 {
+		plan = append(plan, PlanStep{"CREATE", "ExtraManifests", fmt.Sprintf("%d file(s)", len(application.ExtraManifests))})
+	}
+
+	return plan
+}
@@ -2,53 +2,325 @@ package buildpack
 
 import (
 	"encoding/json"
+	"fmt"
+
 	"github.com/pkg/errors"
 	log "github.com/sirupsen/logrus"
 	"time"
 
+	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/kubernetes"
 
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"github.com/snowdrop/spring-boot-cloud-devex/pkg/buildpack/types"
+	"github.com/snowdrop/spring-boot-cloud-devex/pkg/common/oc"
+)
+
+// WaitCondition is the condition WaitAndGetPod blocks until, shared by every
+// command that waits for the dev pod (run, compile, logs, ...) behind a
+// single `--wait-for` flag so there's one place that defines what each
+// level actually means.
+type WaitCondition string
+
+const (
+	// WaitForRunning is satisfied as soon as the pod reaches the Running
+	// phase; its containers may still be starting up.
+	WaitForRunning WaitCondition = "running"
+	// WaitForReady additionally waits for the pod's Ready condition, i.e.
+	// its readiness probe (actuator/health) has passed at least once.
+	WaitForReady WaitCondition = "ready"
+	// WaitForHealthy goes one step further and curls actuator/health from
+	// inside the pod directly, rather than trusting the last probe result
+	// the kubelet reported.
+	WaitForHealthy WaitCondition = "healthy"
 )
 
-// WaitAndGetPod block and waits until pod matching selector is in in Running state
-func WaitAndGetPod(c *kubernetes.Clientset, application types.Application) (*corev1.Pod, error) {
+// ParseWaitCondition validates a --wait-for flag value.
+func ParseWaitCondition(value string) (WaitCondition, error) {
+	switch WaitCondition(value) {
+	case WaitForRunning, WaitForReady, WaitForHealthy:
+		return WaitCondition(value), nil
+	default:
+		return "", errors.Errorf("--wait-for: unknown condition '%s', must be one of running, ready, healthy", value)
+	}
+}
+
+// HealthCheckBudget controls how WaitForHealthy tolerates transient
+// failures during startup (e.g. a 503 while the app is still warming up)
+// instead of either declaring healthy on the first success or failing on
+// the first failure.
+type HealthCheckBudget struct {
+	// ConsecutiveSuccesses is how many health checks in a row must pass
+	// before the pod is declared healthy. <= 0 behaves like 1, the
+	// historical "first success wins" behavior.
+	ConsecutiveSuccesses int
+	// MaxFailures gives up early once this many health checks have failed
+	// in total, instead of only failing once the overall timeout elapses.
+	// 0 means no cap -- the timeout is the only thing that can fail it.
+	MaxFailures int
+}
+
+// DefaultHealthCheckBudget is WaitAndGetPod's zero-configuration behavior:
+// the first successful health check wins, and only the overall timeout
+// can fail it.
+func DefaultHealthCheckBudget() HealthCheckBudget {
+	return HealthCheckBudget{ConsecutiveSuccesses: 1, MaxFailures: 0}
+}
+
+// podWaitTimeout bounds WaitAndGetPod's whole wait, across every condition.
+const podWaitTimeout = 30 * time.Second
+
+// podBackoff caps the fallback poll at 8 attempts, with the sleeps between
+// them doubling from 250ms, i.e. 250ms, 500ms, 1s, 2s, 4s, 8s, 16s (~31.75s
+// total, cut short by WaitAndGetPod's own deadline), so a watch that
+// keeps dropping its connection still converges without hammering the API
+// server on every retry.
+var podBackoff = wait.Backoff{
+	Duration: 250 * time.Millisecond,
+	Factor:   2,
+	Steps:    8,
+}
+
+// WaitAndGetPod blocks until a pod matching application's selector reaches
+// condition, preferring a watch (a single long-lived connection) for the
+// initial Running wait and falling back to capped exponential-backoff
+// polling if the watch's result channel closes before one arrives (e.g. the
+// API server proxy drops it) or before the first Watch call itself can be
+// established.
+func WaitAndGetPod(c kubernetes.Interface, application types.Application, condition WaitCondition, healthCheckBudget HealthCheckBudget) (*corev1.Pod, error) {
 
 	selector := podSelector(application)
 	log.Debugf("Waiting for %s pod", selector)
 
+	deadline := time.Now().Add(podWaitTimeout)
+
+	pod, err := waitForRunningPod(c, application, selector, deadline)
+	if err != nil {
+		return nil, err
+	}
+
+	if condition == WaitForReady || condition == WaitForHealthy {
+		if err := waitForPodReady(c, application, pod.Name, deadline); err != nil {
+			return nil, err
+		}
+	}
+
+	if condition == WaitForHealthy {
+		if err := waitForHealthy(application, pod.Name, deadline, healthCheckBudget); err != nil {
+			return nil, err
+		}
+	}
+
+	return pod, nil
+}
+
+// waitForRunningPod is WaitAndGetPod's WaitForRunning step: find a pod in
+// the Running phase, via watch then poll fallback.
+func waitForRunningPod(c kubernetes.Interface, application types.Application, selector metav1.ListOptions, deadline time.Time) (*corev1.Pod, error) {
+	pod, watchErr := watchForPod(c, application, selector, deadline)
+	if watchErr == nil {
+		return pod, nil
+	}
+	log.Debugf("Watch-based wait failed (%s), falling back to polling", watchErr.Error())
+
+	pod, pollErr := pollForPod(c, application, selector, deadline)
+	if pollErr == nil {
+		return pod, nil
+	}
+
+	bytes, e := json.Marshal(selector)
+	if e != nil {
+		return nil, errors.Errorf("Couldn't marshall pod selector to JSON: %s", e)
+	}
+	if denial := findAdmissionDenial(application.Namespace); denial != "" {
+		return nil, errors.Errorf("Pod was rejected by the cluster: %s", denial)
+	}
+	return nil, errors.Errorf("Waited %s but couldn't find pod matching '%s' selector", podWaitTimeout, string(bytes))
+}
+
+// waitForPodReady polls podName until its PodReady condition is True.
+func waitForPodReady(c kubernetes.Interface, application types.Application, podName string, deadline time.Time) error {
+	err := wait.PollImmediate(2*time.Second, time.Until(deadline), func() (bool, error) {
+		pod, err := c.CoreV1().Pods(application.Namespace).Get(podName, metav1.GetOptions{})
+		if err != nil {
+			return false, err
+		}
+		for _, condition := range pod.Status.Conditions {
+			if condition.Type == corev1.PodReady {
+				return condition.Status == corev1.ConditionTrue, nil
+			}
+		}
+		return false, nil
+	})
+	if err != nil {
+		return errors.Errorf("Waited %s but pod '%s' never became Ready", podWaitTimeout, podName)
+	}
+	return nil
+}
+
+// waitForHealthy curls actuator/health from inside podName directly, rather
+// than trusting the kubelet's last readiness probe result, which can lag a
+// few seconds behind the application's actual state. budget controls how
+// many consecutive successes are required and how many total failures are
+// tolerated before giving up early, so a handful of transient 503s during
+// startup don't either immediately fail the wait or let a single lucky
+// response declare the app healthy.
+func waitForHealthy(application types.Application, podName string, deadline time.Time, budget HealthCheckBudget) error {
+	port := application.Port
+	if application.ManagementPort != 0 {
+		port = application.ManagementPort
+	}
+
+	consecutiveSuccessesNeeded := budget.ConsecutiveSuccesses
+	if consecutiveSuccessesNeeded <= 0 {
+		consecutiveSuccessesNeeded = 1
+	}
+
+	consecutiveSuccesses := 0
+	totalFailures := 0
+
+	err := wait.PollImmediate(2*time.Second, time.Until(deadline), func() (bool, error) {
+		_, err := oc.ExecCommandAndReturn(oc.Command{Args: []string{
+			"exec", podName, "--",
+			"curl", "-sf", fmt.Sprintf("http://localhost:%d/actuator/health", port),
+		}})
+		if err != nil {
+			consecutiveSuccesses = 0
+			totalFailures++
+			if budget.MaxFailures > 0 && totalFailures >= budget.MaxFailures {
+				return false, errors.Errorf("gave up after %d failed health checks", totalFailures)
+			}
+			return false, nil
+		}
+
+		consecutiveSuccesses++
+		return consecutiveSuccesses >= consecutiveSuccessesNeeded, nil
+	})
+	if err == nil {
+		return nil
+	}
+	if err == wait.ErrWaitTimeout {
+		return errors.Errorf("Waited %s but pod '%s' never reported %d consecutive healthy actuator/health checks", podWaitTimeout, podName, consecutiveSuccessesNeeded)
+	}
+	return errors.Wrapf(err, "pod '%s' failed its health-check budget", podName)
+}
+
+// watchForPod waits for a single Pod event from a Watch call, up to
+// deadline, returning an error if the watch can't be established, its
+// channel closes first, or the deadline is reached without an event.
+func watchForPod(c kubernetes.Interface, application types.Application, selector metav1.ListOptions, deadline time.Time) (*corev1.Pod, error) {
 	w, err := c.CoreV1().Pods(application.Namespace).Watch(selector)
 	if err != nil {
 		return nil, errors.Wrapf(err, "unable to watch pod")
 	}
 	defer w.Stop()
 
-	const timeoutInSeconds = 30
-	duration := timeoutInSeconds * time.Second
 	select {
-	case val := <-w.ResultChan():
+	case val, ok := <-w.ResultChan():
+		if !ok {
+			return nil, errors.Errorf("watch channel closed before a matching pod appeared")
+		}
 		log.Debugf("Received event of type %s", val.Type)
-		if pod, ok := val.Object.(*corev1.Pod); ok {
-			return pod, nil
-		} else {
+		pod, ok := val.Object.(*corev1.Pod)
+		if !ok {
 			return nil, errors.Errorf("Unable to convert event object to Pod")
 		}
-	case <-time.After(duration):
-		bytes, e := json.Marshal(selector)
-		if e != nil {
-			return nil, errors.Errorf("Couldn't marshall pod selector to JSON: %s", e)
+		return pod, nil
+	case <-time.After(time.Until(deadline)):
+		return nil, errors.Errorf("timed out waiting for a watch event")
+	}
+}
+
+// pollForPod lists pods matching selector on a capped exponential backoff
+// until one is found or deadline passes.
+func pollForPod(c kubernetes.Interface, application types.Application, selector metav1.ListOptions, deadline time.Time) (*corev1.Pod, error) {
+	var found *corev1.Pod
+
+	err := wait.ExponentialBackoff(podBackoff, func() (bool, error) {
+		if time.Now().After(deadline) {
+			return false, wait.ErrWaitTimeout
+		}
+
+		list, err := c.CoreV1().Pods(application.Namespace).List(selector)
+		if err != nil {
+			return false, err
+		}
+		if len(list.Items) == 0 {
+			return false, nil
 		}
-		return nil, errors.Errorf("Waited %s but couldn't find pod matching '%s' selector", duration, string(bytes))
+
+		found = &list.Items[0]
+		return true, nil
+	})
+	if err != nil {
+		return nil, err
 	}
+	return found, nil
+}
 
-	bytes, e := json.Marshal(selector)
-	if e != nil {
-		return nil, errors.Errorf("Couldn't marshall pod selector to JSON in unknown error code-path. JSON error is: %s", e)
+// findAdmissionDenial looks for a recent FailedCreate event in namespace, so
+// a pod rejected by the SCC/PodSecurity admission plugin (e.g. a
+// restricted-v2 violation) surfaces as a clear error instead of the generic
+// pod-watch timeout.
+func findAdmissionDenial(namespace string) string {
+	out, err := oc.ExecCommandAndReturn(oc.Command{Args: []string{
+		"get", "events", "-n", namespace,
+		"--field-selector", "reason=FailedCreate",
+		"-o", "jsonpath={.items[*].message}",
+	}})
+	if err != nil {
+		return ""
+	}
+	return out
+}
+
+// WaitForPodDeleted polls until no pod matching application's selector
+// remains (e.g. after deleting its DeploymentConfig), or returns an error
+// once timeout elapses. Used before deleting the m2-data PVC, so it isn't
+// removed while a terminating pod still has it mounted.
+func WaitForPodDeleted(c kubernetes.Interface, application types.Application, timeout time.Duration) error {
+	selector := metav1.ListOptions{LabelSelector: "app=" + application.Name}
+
+	err := wait.PollImmediate(2*time.Second, timeout, func() (bool, error) {
+		list, err := c.CoreV1().Pods(application.Namespace).List(selector)
+		if err != nil {
+			return false, err
+		}
+		return len(list.Items) == 0, nil
+	})
+	if err != nil {
+		return errors.Errorf("timed out waiting for %s's pod to terminate", application.Name)
+	}
+	return nil
+}
+
+// DumpDiagnostics prints a pod describe (events, container statuses), the
+// last 50 log lines, and the DeploymentConfig's rollout status for
+// application, turning a `--wait-for` timeout into an actionable report
+// instead of just "waited Ns". Best-effort: each step is skipped silently
+// if it errors, since the pod may not even exist yet.
+func DumpDiagnostics(application types.Application) {
+	log.Warn("Collecting diagnostics ...")
+
+	if out, err := oc.ExecCommandAndReturn(oc.Command{Args: []string{
+		"describe", "pod", "-l", "app=" + application.Name, "-n", application.Namespace,
+	}}); err == nil {
+		fmt.Println(out)
+	}
+
+	if out, err := oc.ExecCommandAndReturn(oc.Command{Args: []string{
+		"logs", "-l", "app=" + application.Name, "-n", application.Namespace, "--tail=50", "--all-containers",
+	}}); err == nil {
+		fmt.Println(out)
+	}
+
+	if out, err := oc.ExecCommandAndReturn(oc.Command{Args: []string{
+		"rollout", "status", "dc/" + application.Name, "-n", application.Namespace,
+	}}); err == nil {
+		fmt.Println(out)
 	}
-	return nil, errors.Errorf("Unknown error while waiting for pod matching '%s' selector", string(bytes))
 }
 
 func podSelector(application types.Application) metav1.ListOptions {
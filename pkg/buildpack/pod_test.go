@@ -0,0 +1,80 @@
+package buildpack_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	k8stesting "k8s.io/client-go/testing"
+
+	"github.com/snowdrop/spring-boot-cloud-devex/pkg/buildpack"
+	"github.com/snowdrop/spring-boot-cloud-devex/pkg/buildpack/types"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+// TestWaitAndGetPodFallsBackToBoundedPolling makes the fake clientset's
+// Watch call fail, forcing WaitAndGetPod onto its poll fallback, and counts
+// how many "list" calls land on the API. A tight poll loop over the 30s
+// timeout would issue hundreds of calls; the capped exponential backoff
+// should issue only a handful before the pod is found.
+func TestWaitAndGetPodFallsBackToBoundedPolling(t *testing.T) {
+	application := types.Application{Name: "pod-test", Namespace: "dev"}
+
+	pod := corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "pod-test-1",
+			Namespace: "dev",
+			Labels:    map[string]string{"app": application.Name},
+		},
+		Status: corev1.PodStatus{Phase: corev1.PodRunning},
+	}
+
+	clientset := fake.NewSimpleClientset()
+
+	clientset.PrependWatchReactor("pods", func(action k8stesting.Action) (bool, watch.Interface, error) {
+		return true, nil, errors.New("watch not supported in this test")
+	})
+
+	var listCalls int
+	clientset.PrependReactor("list", "pods", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		listCalls++
+		// Only "find" the pod on the 3rd call, so backoff has to retry but
+		// should still converge well within the deadline.
+		if listCalls < 3 {
+			return true, &corev1.PodList{}, nil
+		}
+		return true, &corev1.PodList{Items: []corev1.Pod{pod}}, nil
+	})
+
+	start := time.Now()
+	got, err := buildpack.WaitAndGetPod(clientset, application, buildpack.WaitForRunning, buildpack.DefaultHealthCheckBudget())
+	elapsed := time.Since(start)
+
+	if err != nil {
+		t.Fatalf("WaitAndGetPod returned an error: %s", err.Error())
+	}
+	if got.Name != pod.Name {
+		t.Errorf("got pod '%s', want '%s'", got.Name, pod.Name)
+	}
+	if listCalls > 10 {
+		t.Errorf("expected a bounded number of list calls, got %d", listCalls)
+	}
+	if elapsed > 10*time.Second {
+		t.Errorf("expected WaitAndGetPod to return promptly once the pod appears, took %s", elapsed)
+	}
+}
+
+func TestDefaultHealthCheckBudget(t *testing.T) {
+	budget := buildpack.DefaultHealthCheckBudget()
+
+	if budget.ConsecutiveSuccesses != 1 {
+		t.Errorf("ConsecutiveSuccesses = %d, want 1 (first success wins)", budget.ConsecutiveSuccesses)
+	}
+	if budget.MaxFailures != 0 {
+		t.Errorf("MaxFailures = %d, want 0 (no cap, timeout only)", budget.MaxFailures)
+	}
+}
@@ -0,0 +1,63 @@
+package buildpack
+
+import (
+	"net"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes"
+	restclient "k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/portforward"
+	"k8s.io/client-go/transport/spdy"
+)
+
+// checkLocalPortsFree binds and immediately releases each "LOCAL:REMOTE"
+// pair's local port, so a port already in use on this machine is reported
+// with a clear error naming it, instead of surfacing as an opaque dial
+// failure once portforward.ForwardPorts() is already running.
+func checkLocalPortsFree(ports []string) error {
+	for _, pair := range ports {
+		localPort := strings.SplitN(pair, ":", 2)[0]
+
+		ln, err := net.Listen("tcp", ":"+localPort)
+		if err != nil {
+			return errors.Wrapf(err, "local port %s is already in use", localPort)
+		}
+		ln.Close()
+	}
+	return nil
+}
+
+// PortForward forwards one or more "LOCAL:REMOTE" port pairs to pod over a
+// SPDY upgraded connection, blocking until stopChan is closed or the
+// connection fails. readyChan, if non-nil, is closed once the forwarder is
+// listening on every local port.
+func PortForward(config *restclient.Config, clientset *kubernetes.Clientset, pod *corev1.Pod, ports []string, stopChan <-chan struct{}, readyChan chan struct{}) error {
+	if err := checkLocalPortsFree(ports); err != nil {
+		return err
+	}
+
+	req := clientset.CoreV1().RESTClient().Post().
+		Resource("pods").
+		Namespace(pod.Namespace).
+		Name(pod.Name).
+		SubResource("portforward")
+
+	transport, upgrader, err := spdy.RoundTripperFor(config)
+	if err != nil {
+		return err
+	}
+
+	dialer := spdy.NewDialer(upgrader, &http.Client{Transport: transport}, "POST", req.URL())
+
+	fw, err := portforward.New(dialer, ports, stopChan, readyChan, os.Stdout, os.Stderr)
+	if err != nil {
+		return err
+	}
+
+	return fw.ForwardPorts()
+}
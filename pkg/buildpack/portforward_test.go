@@ -0,0 +1,28 @@
+package buildpack_test
+
+import (
+	"net"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/snowdrop/spring-boot-cloud-devex/pkg/buildpack"
+)
+
+func TestPortForwardRejectsLocalPortAlreadyInUse(t *testing.T) {
+	ln, err := net.Listen("tcp", ":0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+
+	localPort := strconv.Itoa(ln.Addr().(*net.TCPAddr).Port)
+
+	err = buildpack.PortForward(nil, nil, nil, []string{localPort + ":8080"}, nil, nil)
+	if err == nil {
+		t.Fatal("PortForward returned no error for a local port already in use")
+	}
+	if !strings.Contains(err.Error(), localPort) {
+		t.Errorf("error %q does not name the offending local port %q", err.Error(), localPort)
+	}
+}
@@ -0,0 +1,107 @@
+package buildpack
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"regexp"
+	"strconv"
+
+	"github.com/snowdrop/spring-boot-cloud-devex/pkg/buildpack/types"
+)
+
+// ProbeSettings holds the readiness/liveness HTTP paths and timing a
+// ProbePreset resolves to. BuildDeploymentConfig builds one corev1.Probe
+// per path.
+type ProbeSettings struct {
+	ReadinessPath       string
+	LivenessPath        string
+	InitialDelaySeconds int32
+	PeriodSeconds       int32
+}
+
+// probePresets maps each concrete ProbePreset to the paths and timing
+// Spring Boot Actuator warrants for that generation. ProbePresetSpringBoot3's
+// split readiness/liveness groups were added in Spring Boot 2.3 and are
+// what Boot 3's own Kubernetes documentation recommends probing instead;
+// ProbePresetSpringBoot2 keeps the single combined endpoint every earlier
+// 2.x application already exposes, with a longer InitialDelaySeconds since
+// the combined check also waits on non-readiness concerns (e.g. DB
+// connection pools) to settle; ProbePresetLegacy covers pre-Actuator-2
+// applications that only ever served "/health", with a longer delay still
+// since it predates Spring Boot's own startup/readiness signalling.
+var probePresets = map[string]ProbeSettings{
+	types.ProbePresetSpringBoot2: {ReadinessPath: "/actuator/health", LivenessPath: "/actuator/health", InitialDelaySeconds: 20, PeriodSeconds: 10},
+	types.ProbePresetSpringBoot3: {ReadinessPath: "/actuator/health/readiness", LivenessPath: "/actuator/health/liveness", InitialDelaySeconds: 10, PeriodSeconds: 10},
+	types.ProbePresetLegacy:      {ReadinessPath: "/health", LivenessPath: "/health", InitialDelaySeconds: 30, PeriodSeconds: 10},
+}
+
+// mavenSpringBootVersionPattern picks the major version digit out of a
+// pom.xml's <parent><artifactId>spring-boot-starter-parent</artifactId>.
+var mavenSpringBootVersionPattern = regexp.MustCompile(`<artifactId>\s*spring-boot-starter-parent\s*</artifactId>\s*<version>\s*(\d+)\.`)
+
+// gradleSpringBootVersionPattern picks the major version digit out of a
+// build.gradle/build.gradle.kts "org.springframework.boot" plugin
+// declaration, Groovy or Kotlin DSL.
+var gradleSpringBootVersionPattern = regexp.MustCompile(`org\.springframework\.boot['"]?\s*\)?\s*version\s*['"](\d+)\.`)
+
+// ResolveProbePreset resolves the types.ProbePresetAuto setting to a
+// concrete value by looking for a Spring Boot version declaration in
+// dir's pom.xml, then build.gradle, then build.gradle.kts: major version
+// 3 and up resolves to ProbePresetSpringBoot3, anything older -- or no
+// version found at all -- falls back to ProbePresetSpringBoot2, the
+// generation the vast majority of existing MANIFESTs target. Any other
+// preset is returned unchanged, since it was already a concrete choice.
+func ResolveProbePreset(dir string, preset string) string {
+	if preset != types.ProbePresetAuto {
+		return preset
+	}
+
+	if major, ok := detectSpringBootMajor(dir); ok && major >= 3 {
+		return types.ProbePresetSpringBoot3
+	}
+
+	return types.ProbePresetSpringBoot2
+}
+
+func detectSpringBootMajor(dir string) (int, bool) {
+	if data, err := ioutil.ReadFile(filepath.Join(dir, "pom.xml")); err == nil {
+		if major, ok := findSpringBootMajor(mavenSpringBootVersionPattern, data); ok {
+			return major, true
+		}
+	}
+
+	for _, candidate := range []string{"build.gradle", "build.gradle.kts"} {
+		data, err := ioutil.ReadFile(filepath.Join(dir, candidate))
+		if err != nil {
+			continue
+		}
+		if major, ok := findSpringBootMajor(gradleSpringBootVersionPattern, data); ok {
+			return major, true
+		}
+	}
+
+	return 0, false
+}
+
+func findSpringBootMajor(pattern *regexp.Regexp, data []byte) (int, bool) {
+	match := pattern.FindSubmatch(data)
+	if match == nil {
+		return 0, false
+	}
+
+	major, err := strconv.Atoi(string(match[1]))
+	if err != nil {
+		return 0, false
+	}
+	return major, true
+}
+
+// ProbeSettingsFor returns preset's readiness/liveness paths, falling back
+// to ProbePresetSpringBoot2's when preset is empty or unrecognized (e.g. a
+// MANIFEST written before ProbePreset existed).
+func ProbeSettingsFor(preset string) ProbeSettings {
+	if settings, ok := probePresets[preset]; ok {
+		return settings
+	}
+	return probePresets[types.ProbePresetSpringBoot2]
+}
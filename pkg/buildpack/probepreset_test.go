@@ -0,0 +1,95 @@
+package buildpack_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/snowdrop/spring-boot-cloud-devex/pkg/buildpack"
+	"github.com/snowdrop/spring-boot-cloud-devex/pkg/buildpack/types"
+)
+
+func TestResolveProbePreset(t *testing.T) {
+	cases := []struct {
+		name     string
+		preset   string
+		fileName string
+		content  string
+		want     string
+	}{
+		{"explicit springboot2 passes through", types.ProbePresetSpringBoot2, "", "", types.ProbePresetSpringBoot2},
+		{"explicit springboot3 passes through", types.ProbePresetSpringBoot3, "", "", types.ProbePresetSpringBoot3},
+		{"explicit legacy passes through", types.ProbePresetLegacy, "", "", types.ProbePresetLegacy},
+		{
+			"auto detects springboot3 from pom.xml",
+			types.ProbePresetAuto, "pom.xml",
+			"<project><parent><artifactId>spring-boot-starter-parent</artifactId><version>3.1.0</version></parent></project>",
+			types.ProbePresetSpringBoot3,
+		},
+		{
+			"auto detects springboot2 from pom.xml",
+			types.ProbePresetAuto, "pom.xml",
+			"<project><parent><artifactId>spring-boot-starter-parent</artifactId><version>2.7.5</version></parent></project>",
+			types.ProbePresetSpringBoot2,
+		},
+		{
+			"auto detects springboot3 from build.gradle",
+			types.ProbePresetAuto, "build.gradle",
+			"plugins {\n\tid 'org.springframework.boot' version '3.0.2'\n}\n",
+			types.ProbePresetSpringBoot3,
+		},
+		{
+			"auto falls back to springboot2 when no version is found",
+			types.ProbePresetAuto, "", "",
+			types.ProbePresetSpringBoot2,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			dir, err := ioutil.TempDir("", "probepreset-test-")
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer os.RemoveAll(dir)
+
+			if c.fileName != "" {
+				if err := ioutil.WriteFile(filepath.Join(dir, c.fileName), []byte(c.content), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			if got := buildpack.ResolveProbePreset(dir, c.preset); got != c.want {
+				t.Errorf("ResolveProbePreset() = %q, want %q", got, c.want)
+			}
+		})
+	}
+}
+
+func TestProbeSettingsFor(t *testing.T) {
+	cases := []struct {
+		name          string
+		preset        string
+		readinessPath string
+		livenessPath  string
+	}{
+		{"springboot2", types.ProbePresetSpringBoot2, "/actuator/health", "/actuator/health"},
+		{"springboot3", types.ProbePresetSpringBoot3, "/actuator/health/readiness", "/actuator/health/liveness"},
+		{"legacy", types.ProbePresetLegacy, "/health", "/health"},
+		{"unrecognized falls back to springboot2", "bogus", "/actuator/health", "/actuator/health"},
+		{"empty falls back to springboot2", "", "/actuator/health", "/actuator/health"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got := buildpack.ProbeSettingsFor(c.preset)
+			if got.ReadinessPath != c.readinessPath || got.LivenessPath != c.livenessPath {
+				t.Errorf("ProbeSettingsFor(%q) = %+v, want {%q %q}", c.preset, got, c.readinessPath, c.livenessPath)
+			}
+			if got.InitialDelaySeconds <= 0 || got.PeriodSeconds <= 0 {
+				t.Errorf("ProbeSettingsFor(%q) = %+v, want positive timing", c.preset, got)
+			}
+		})
+	}
+}
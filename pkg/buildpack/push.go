@@ -0,0 +1,259 @@
+package buildpack
+
+import (
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// sdIgnoreFile is the optional newline-separated glob pattern file at the
+// project root that --delete-extra consults to skip paths it lists, so
+// generated or intentionally pod-only files aren't reported as stale.
+const sdIgnoreFile = ".sdignore"
+
+// LoadIgnorePatterns reads dir's .sdignore file, if present, returning one
+// glob pattern per non-empty, non-comment line. A missing file yields no
+// patterns, since .sdignore is optional.
+func LoadIgnorePatterns(dir string) []string {
+	data, err := ioutil.ReadFile(filepath.Join(dir, sdIgnoreFile))
+	if err != nil {
+		return nil
+	}
+
+	var patterns []string
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		patterns = append(patterns, line)
+	}
+	return patterns
+}
+
+// isIgnored reports whether relPath (slash-separated, relative to the
+// project root) matches one of patterns, checked against both the full
+// path and its base name so a pattern like "*.class" matches at any depth.
+func isIgnored(relPath string, patterns []string) bool {
+	base := filepath.Base(relPath)
+	for _, pattern := range patterns {
+		if matched, _ := filepath.Match(pattern, relPath); matched {
+			return true
+		}
+		if matched, _ := filepath.Match(pattern, base); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// LocalFileSet walks srcDir and returns the slash-separated paths of its
+// files, relative to srcDir, skipping anything patterns (from
+// LoadIgnorePatterns) matches.
+func LocalFileSet(srcDir string, patterns []string) (map[string]bool, error) {
+	files := map[string]bool{}
+
+	err := filepath.Walk(srcDir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			return nil
+		}
+
+		relPath, err := filepath.Rel(srcDir, path)
+		if err != nil {
+			return err
+		}
+		relPath = filepath.ToSlash(relPath)
+
+		if isIgnored(relPath, patterns) {
+			return nil
+		}
+		files[relPath] = true
+		return nil
+	})
+
+	return files, err
+}
+
+// LargeFiles walks srcDir and returns the slash-separated paths, relative to
+// srcDir, of files larger than maxBytes, skipping anything patterns (from
+// LoadIgnorePatterns) matches. It's `sd push --max-file-size`'s detector for
+// an accidentally-synced multi-GB build artifact or local database.
+func LargeFiles(srcDir string, patterns []string, maxBytes int64) ([]string, error) {
+	var large []string
+
+	err := filepath.Walk(srcDir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			return nil
+		}
+
+		relPath, err := filepath.Rel(srcDir, path)
+		if err != nil {
+			return err
+		}
+		relPath = filepath.ToSlash(relPath)
+
+		if isIgnored(relPath, patterns) {
+			return nil
+		}
+		if info.Size() > maxBytes {
+			large = append(large, relPath)
+		}
+		return nil
+	})
+
+	return large, err
+}
+
+// RemoteExtraFiles parses remoteListing (the newline-separated output of
+// `find remoteDir -type f` inside the pod) and returns the full remote
+// paths that have no corresponding entry in localFiles (as returned by
+// LocalFileSet, keyed relative to the local directory mirrored at
+// remoteDir), for `sd push --delete-extra` to remove.
+func RemoteExtraFiles(remoteListing string, remoteDir string, localFiles map[string]bool) []string {
+	var extra []string
+
+	for _, line := range strings.Split(remoteListing, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+
+		relPath, err := filepath.Rel(remoteDir, line)
+		if err != nil || relPath == "." {
+			continue
+		}
+		relPath = filepath.ToSlash(relPath)
+
+		if !localFiles[relPath] {
+			extra = append(extra, line)
+		}
+	}
+
+	return extra
+}
+
+// ResolveSymlinksForPush walks srcDir and, when followSymlinks is true,
+// materializes a copy of it under a temp directory with every symlink
+// replaced by the contents of its target, so the result can be handed to
+// CopyToPod as a plain directory tree. Cycles (a link pointing back into a
+// directory already being walked) are detected via the resolved real path
+// and skipped rather than followed forever.
+//
+// When followSymlinks is false, srcDir is returned unchanged: CopyToPod's
+// tar stream preserves symlinks as links on its own, so there is nothing
+// to do.
+//
+// The returned cleanup func removes the temp directory, if one was
+// created, and must be called once the caller is done with the result.
+func ResolveSymlinksForPush(srcDir string, followSymlinks bool) (string, func(), error) {
+	noop := func() {}
+
+	if !followSymlinks {
+		return srcDir, noop, nil
+	}
+
+	stagingDir, err := ioutil.TempDir("", "sd-push-")
+	if err != nil {
+		return "", noop, errors.Wrap(err, "unable to create staging directory for --follow-symlinks")
+	}
+	cleanup := func() { os.RemoveAll(stagingDir) }
+
+	visited := map[string]bool{}
+	if err := copyResolvingSymlinks(srcDir, stagingDir, visited); err != nil {
+		cleanup()
+		return "", noop, errors.Wrapf(err, "unable to resolve symlinks under '%s'", srcDir)
+	}
+
+	return stagingDir, cleanup, nil
+}
+
+func copyResolvingSymlinks(src string, dst string, visited map[string]bool) error {
+	realSrc, err := filepath.EvalSymlinks(src)
+	if err != nil {
+		return err
+	}
+	if visited[realSrc] {
+		return nil
+	}
+	visited[realSrc] = true
+
+	info, err := os.Stat(realSrc)
+	if err != nil {
+		return err
+	}
+
+	if info.IsDir() {
+		if err := os.MkdirAll(dst, info.Mode()); err != nil {
+			return err
+		}
+
+		entries, err := ioutil.ReadDir(realSrc)
+		if err != nil {
+			return err
+		}
+
+		for _, entry := range entries {
+			if err := copyResolvingSymlinks(filepath.Join(realSrc, entry.Name()), filepath.Join(dst, entry.Name()), visited); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+
+	return copyFile(realSrc, dst, info)
+}
+
+// StageRenamed copies srcPath into a fresh temporary directory under
+// destName, so CopyToPod -- which always preserves localPath's own base
+// name in the tar stream -- can be used to push a single file under a
+// different name (e.g. "myapp-1.0.jar" on disk as "/deployments/app.jar" in
+// the pod). Callers must call the returned cleanup func once done.
+func StageRenamed(srcPath string, destName string) (string, func(), error) {
+	stagingDir, err := ioutil.TempDir("", "sd-push-")
+	if err != nil {
+		return "", func() {}, errors.Wrap(err, "unable to create staging directory")
+	}
+	cleanup := func() { os.RemoveAll(stagingDir) }
+
+	info, err := os.Stat(srcPath)
+	if err != nil {
+		cleanup()
+		return "", func() {}, err
+	}
+
+	renamedPath := filepath.Join(stagingDir, destName)
+	if err := copyFile(srcPath, renamedPath, info); err != nil {
+		cleanup()
+		return "", func() {}, errors.Wrapf(err, "unable to stage '%s' as '%s'", srcPath, destName)
+	}
+
+	return renamedPath, cleanup, nil
+}
+
+func copyFile(src string, dst string, info os.FileInfo) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(dst, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, info.Mode())
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	_, err = io.Copy(out, in)
+	return err
+}
@@ -0,0 +1,75 @@
+package buildpack_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/snowdrop/spring-boot-cloud-devex/pkg/buildpack"
+)
+
+func TestLocalFileSetSkipsIgnoredFiles(t *testing.T) {
+	dir, err := ioutil.TempDir("", "push-test-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	if err := ioutil.WriteFile(filepath.Join(dir, "Main.java"), []byte("x"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(dir, "Main.class"), []byte("x"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	files, err := buildpack.LocalFileSet(dir, []string{"*.class"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !files["Main.java"] {
+		t.Error("expected Main.java to be present")
+	}
+	if files["Main.class"] {
+		t.Error("expected Main.class to be skipped as ignored")
+	}
+}
+
+func TestLargeFilesFindsFilesOverThreshold(t *testing.T) {
+	dir, err := ioutil.TempDir("", "push-test-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	if err := ioutil.WriteFile(filepath.Join(dir, "small.txt"), []byte("x"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(dir, "huge.bin"), []byte("xxxxxxxxxx"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(dir, "huge.ignored"), []byte("xxxxxxxxxx"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	large, err := buildpack.LargeFiles(dir, []string{"*.ignored"}, 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(large) != 1 || large[0] != "huge.bin" {
+		t.Errorf("LargeFiles = %v, want [\"huge.bin\"]", large)
+	}
+}
+
+func TestRemoteExtraFilesFindsFilesAbsentLocally(t *testing.T) {
+	localFiles := map[string]bool{"Main.java": true}
+	listing := "/tmp/src/src/Main.java\n/tmp/src/src/Old.java\n"
+
+	extra := buildpack.RemoteExtraFiles(listing, "/tmp/src/src", localFiles)
+
+	if len(extra) != 1 || extra[0] != "/tmp/src/src/Old.java" {
+		t.Errorf("RemoteExtraFiles = %v, want [\"/tmp/src/src/Old.java\"]", extra)
+	}
+}
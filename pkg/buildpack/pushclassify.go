@@ -0,0 +1,39 @@
+package buildpack
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+// DefaultRestartExtensions are the file extensions `sd push --auto-restart`
+// treats as requiring a supervisord restart to take effect: Java sources
+// need to recompile, and build/config files change what gets run. Anything
+// else pushed (templates, static assets, ...) is assumed to be something
+// Spring DevTools' own LiveReload/restart-exclude handling already deals
+// with, so push leaves the running process alone.
+var DefaultRestartExtensions = []string{".java", ".class", ".xml", ".properties", ".yml", ".yaml"}
+
+// RequiresRestart reports whether relPath's extension is one of extensions
+// (case-insensitive), the rule NeedsRestart applies per pushed file.
+func RequiresRestart(relPath string, extensions []string) bool {
+	ext := strings.ToLower(filepath.Ext(relPath))
+	for _, candidate := range extensions {
+		if strings.ToLower(candidate) == ext {
+			return true
+		}
+	}
+	return false
+}
+
+// NeedsRestart reports whether any path in pushedFiles requires a
+// supervisord restart per RequiresRestart/extensions, so `sd push` only
+// restarts the application when a changed file actually needs it instead of
+// on every push.
+func NeedsRestart(pushedFiles map[string]bool, extensions []string) bool {
+	for path := range pushedFiles {
+		if RequiresRestart(path, extensions) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,120 @@
+package buildpack
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	"github.com/ghodss/yaml"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/snowdrop/spring-boot-cloud-devex/pkg/buildpack/types"
+)
+
+// WriteManifests renders the DeploymentConfig, Service and Route that `sd
+// init` would create for application and writes each one to
+// dir/<kind>-<name>.yaml, for GitOps workflows that commit the generated
+// resources instead of (or alongside) applying them directly.
+//
+// Files are written in a fixed kind order so repeated runs produce a stable
+// set of diffs.
+func WriteManifests(dir string, application types.Application, commands string, noCache bool) error {
+	resources := []struct {
+		kind   string
+		name   string
+		object interface{}
+	}{
+		{"DeploymentConfig", application.Name, BuildDeploymentConfig(application, commands, noCache)},
+		{"Service", application.Name, BuildService(application)},
+	}
+
+	for _, route := range BuildRoutes(application) {
+		resources = append(resources, struct {
+			kind   string
+			name   string
+			object interface{}
+		}{"Route", route.Name, route})
+	}
+
+	for _, resource := range resources {
+		b, err := yaml.Marshal(resource.object)
+		if err != nil {
+			return err
+		}
+
+		fileName := resource.kind + "-" + resource.name + ".yaml"
+		if err := ioutil.WriteFile(filepath.Join(dir, fileName), b, 0644); err != nil {
+			return err
+		}
+		log.Infof("Wrote %s", filepath.Join(dir, fileName))
+	}
+
+	return nil
+}
+
+// Render kinds accepted by `sd render`.
+const (
+	RenderImageStream      = "imagestream"
+	RenderDeploymentConfig = "deploymentconfig"
+	RenderService          = "service"
+	RenderRoute            = "route"
+	RenderPVC              = "pvc"
+)
+
+// RenderKinds lists every kind Render accepts, in the order `sd render
+// --help` should show them.
+var RenderKinds = []string{RenderImageStream, RenderDeploymentConfig, RenderService, RenderRoute, RenderPVC}
+
+// Render returns the YAML of the resource(s) `sd init` would create for
+// kind, built purely from application via the same Build* functions Setup()
+// itself calls -- so it reflects whatever the MANIFEST (and any CLI
+// overrides already folded into application) actually produce, without
+// touching the cluster. A MANIFEST declaring several Routes renders each as
+// its own "---"-separated document.
+func Render(kind string, application types.Application) (string, error) {
+	switch kind {
+	case RenderImageStream:
+		docs := make([]string, 0, len(defaultImages))
+		for _, imageStream := range BuildImageStreams(application, defaultImages) {
+			doc, err := renderDocument(imageStream)
+			if err != nil {
+				return "", err
+			}
+			docs = append(docs, doc)
+		}
+		return strings.Join(docs, "---\n"), nil
+	case RenderDeploymentConfig:
+		return renderDocument(BuildDeploymentConfig(application, "", false))
+	case RenderService:
+		svc := BuildService(application)
+		return renderDocument(&svc)
+	case RenderRoute:
+		routes := BuildRoutes(application)
+		docs := make([]string, 0, len(routes))
+		for _, route := range routes {
+			doc, err := renderDocument(route)
+			if err != nil {
+				return "", err
+			}
+			docs = append(docs, doc)
+		}
+		return strings.Join(docs, "---\n"), nil
+	case RenderPVC:
+		pvc, err := BuildPVC(application, "1Gi")
+		if err != nil {
+			return "", err
+		}
+		return renderDocument(pvc)
+	default:
+		return "", errors.Errorf("'%s' is not a renderable kind (known: %s)", kind, strings.Join(RenderKinds, ", "))
+	}
+}
+
+func renderDocument(resource interface{}) (string, error) {
+	out, err := yaml.Marshal(resource)
+	if err != nil {
+		return "", errors.Wrap(err, "unable to render YAML")
+	}
+	return string(out), nil
+}
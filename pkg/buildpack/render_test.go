@@ -0,0 +1,36 @@
+package buildpack_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/snowdrop/spring-boot-cloud-devex/pkg/buildpack"
+	"github.com/snowdrop/spring-boot-cloud-devex/pkg/buildpack/types"
+)
+
+func TestRenderKnownKinds(t *testing.T) {
+	application := types.NewApplication()
+	application.Name = "render-test"
+	application.Namespace = "render-ns"
+	application.Port = 8080
+	application.PortName = "http"
+
+	for _, kind := range buildpack.RenderKinds {
+		rendered, err := buildpack.Render(kind, application)
+		if err != nil {
+			t.Fatalf("Render(%q) returned an error: %s", kind, err.Error())
+		}
+		if strings.TrimSpace(rendered) == "" {
+			t.Errorf("Render(%q) returned empty YAML", kind)
+		}
+	}
+}
+
+func TestRenderUnknownKind(t *testing.T) {
+	application := types.NewApplication()
+	application.Name = "render-test"
+
+	if _, err := buildpack.Render("configmap", application); err == nil {
+		t.Error("Render(\"configmap\") = nil error, want one naming the unknown kind")
+	}
+}
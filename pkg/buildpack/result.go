@@ -0,0 +1,72 @@
+package buildpack
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	restclient "k8s.io/client-go/rest"
+
+	"github.com/snowdrop/spring-boot-cloud-devex/pkg/buildpack/types"
+)
+
+// ResourceResult is one created resource's name and whether it actually
+// exists on the cluster, for InitResult.
+type ResourceResult struct {
+	Name   string `json:"name"`
+	Status string `json:"status"`
+}
+
+// RouteResult is one Route's name, URL (once admitted), and status, for
+// InitResult.
+type RouteResult struct {
+	Name   string `json:"name"`
+	URL    string `json:"url,omitempty"`
+	Status string `json:"status"`
+}
+
+// InitResult is everything `sd init --output json` reports after
+// provisioning, so CI can parse what was created and extract a URL for a
+// smoke test without scraping log output.
+type InitResult struct {
+	Namespace        string         `json:"namespace"`
+	DeploymentConfig ResourceResult `json:"deploymentConfig"`
+	Service          ResourceResult `json:"service"`
+	Routes           []RouteResult  `json:"routes"`
+	PodName          string         `json:"podName,omitempty"`
+}
+
+// BuildInitResult reads back application's DeploymentConfig, Service,
+// Routes, and dev pod (if one has been scheduled yet) to describe what `sd
+// init` created. It's read-only -- callers run it after
+// finishSetupAndSetApplicationName has already done the creating.
+func BuildInitResult(config *restclient.Config, clientset kubernetes.Interface, application types.Application) InitResult {
+	result := InitResult{Namespace: application.Namespace}
+
+	if _, err := getAppsClient(config).DeploymentConfigs(application.Namespace).Get(application.Name, metav1.GetOptions{}); err == nil {
+		result.DeploymentConfig = ResourceResult{Name: application.Name, Status: "created"}
+	} else {
+		result.DeploymentConfig = ResourceResult{Name: application.Name, Status: "missing"}
+	}
+
+	if _, err := clientset.CoreV1().Services(application.Namespace).Get(application.Name, metav1.GetOptions{}); err == nil {
+		result.Service = ResourceResult{Name: application.Name, Status: "created"}
+	} else {
+		result.Service = ResourceResult{Name: application.Name, Status: "missing"}
+	}
+
+	if routes, err := GetRoutes(config, application); err == nil {
+		for _, route := range routes {
+			routeResult := RouteResult{Name: route.Name, Status: "pending"}
+			if url, err := RouteURL(route); err == nil {
+				routeResult.URL = url
+				routeResult.Status = "admitted"
+			}
+			result.Routes = append(result.Routes, routeResult)
+		}
+	}
+
+	if pods, err := clientset.CoreV1().Pods(application.Namespace).List(metav1.ListOptions{LabelSelector: "app=" + application.Name}); err == nil && len(pods.Items) > 0 {
+		result.PodName = pods.Items[0].Name
+	}
+
+	return result
+}
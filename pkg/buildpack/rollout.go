@@ -0,0 +1,72 @@
+package buildpack
+
+import (
+	log "github.com/sirupsen/logrus"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	restclient "k8s.io/client-go/rest"
+	"k8s.io/client-go/util/retry"
+
+	appsv1 "github.com/openshift/api/apps/v1"
+
+	"github.com/snowdrop/spring-boot-cloud-devex/pkg/buildpack/types"
+)
+
+// GetDeploymentConfig fetches application's live DeploymentConfig.
+func GetDeploymentConfig(config *restclient.Config, application types.Application) (*appsv1.DeploymentConfig, error) {
+	return getAppsClient(config).DeploymentConfigs(application.Namespace).Get(application.Name, metav1.GetOptions{})
+}
+
+// PauseRollout sets the DeploymentConfig's paused field, which stops
+// OpenShift's DC controller from triggering a new deployment on a config or
+// image change. `sd env`/`sd push` still update the DC itself while
+// paused; OpenShift queues those changes and rolls them out together the
+// next time the DC is resumed.
+func PauseRollout(config *restclient.Config, application types.Application) error {
+	return setRolloutPaused(config, application, true)
+}
+
+// ResumeRollout clears the DeploymentConfig's paused field, letting the DC
+// controller roll out whatever changes accumulated while it was paused.
+func ResumeRollout(config *restclient.Config, application types.Application) error {
+	return setRolloutPaused(config, application, false)
+}
+
+func setRolloutPaused(config *restclient.Config, application types.Application, paused bool) error {
+	dcClient := getAppsClient(config).DeploymentConfigs(application.Namespace)
+
+	alreadySet := false
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		dc, err := dcClient.Get(application.Name, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+
+		if dc.Spec.Paused == paused {
+			alreadySet = true
+			return nil
+		}
+
+		dc.Spec.Paused = paused
+		_, err = dcClient.Update(dc)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	if alreadySet {
+		log.Infof("'%s' is already %s", application.Name, pausedWord(paused))
+		return nil
+	}
+
+	log.Infof("'%s' is now %s", application.Name, pausedWord(paused))
+	return nil
+}
+
+func pausedWord(paused bool) string {
+	if paused {
+		return "paused"
+	}
+	return "resumed"
+}
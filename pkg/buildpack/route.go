@@ -2,44 +2,128 @@ package buildpack
 
 import (
 	"github.com/ghodss/yaml"
+	"github.com/pkg/errors"
 	log "github.com/sirupsen/logrus"
 
 	restclient "k8s.io/client-go/rest"
 
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
 	routev1 "github.com/openshift/api/route/v1"
 	routeclientsetv1 "github.com/openshift/client-go/route/clientset/versioned/typed/route/v1"
 
 	"github.com/snowdrop/spring-boot-cloud-devex/pkg/buildpack/types"
-	"github.com/snowdrop/spring-boot-cloud-devex/pkg/common/oc"
+	cfg "github.com/snowdrop/spring-boot-cloud-devex/pkg/common/config"
 	"strings"
 )
 
-func CreateRouteTemplate(config *restclient.Config, application types.Application) {
-	if oc.Exists("route", application.Name) {
-		log.Infof("'%s' Route already exists, skipping", application.Name)
-	} else {
-		routeV1Client := getClient(config)
-
-		// Parse Route Template
-		tName := strings.Join([]string{builderPath, "route"}, "/")
-		var b = ParseTemplate(tName, application)
-
-		// Create Route struct using the generated Route string
-		route := routev1.Route{}
-		errYamlParsing := yaml.Unmarshal(b.Bytes(), &route)
-		if errYamlParsing != nil {
-			panic(errYamlParsing)
+// routeTemplateData is the template context for a single rendered Route. It
+// pairs one entry of Application.Routes (or the synthesized default) with
+// the application name, which always backs the target Service.
+type routeTemplateData struct {
+	Name        string
+	ServiceName string
+	Host        string
+	Path        string
+	TargetPort  string
+	TLS         bool
+}
+
+// applicationRoutes returns application.Routes, or a single Route named
+// after the application exposing its main port when the MANIFEST declares
+// none.
+func applicationRoutes(application types.Application) []types.Route {
+	if len(application.Routes) > 0 {
+		return application.Routes
+	}
+
+	return []types.Route{
+		{
+			Name:       application.Name,
+			TargetPort: application.PortName,
+		},
+	}
+}
+
+// BuildRoute returns the Route that would be created for the given entry of
+// application.Routes, without touching the cluster.
+func BuildRoute(application types.Application, route types.Route) routev1.Route {
+	host := route.Host
+	if host == "" && application.RouteDomain != "" {
+		host = route.Name + "." + application.RouteDomain
+	}
+
+	tName := strings.Join([]string{builderPath, "route"}, "/")
+	data := routeTemplateData{
+		Name:        route.Name,
+		ServiceName: application.Name,
+		Host:        host,
+		Path:        route.Path,
+		TargetPort:  route.TargetPort,
+		TLS:         route.TLS,
+	}
+	var b = ParseTemplate(tName, data)
+
+	built := routev1.Route{}
+	errYamlParsing := yaml.Unmarshal(b.Bytes(), &built)
+	if errYamlParsing != nil {
+		panic(errYamlParsing)
+	}
+	built.Labels = MergeLabels(application.Labels, built.Labels)
+	built.Labels[OdoLabelName] = OdoLabelValue
+	return built
+}
+
+// BuildRoutes returns every Route that would be created for application,
+// defaulting to a single one named after the application when its MANIFEST
+// declares no `routes:` section.
+func BuildRoutes(application types.Application) []routev1.Route {
+	declared := applicationRoutes(application)
+	built := make([]routev1.Route, 0, len(declared))
+	for _, route := range declared {
+		built = append(built, BuildRoute(application, route))
+	}
+	return built
+}
+
+// CreateRouteTemplate creates every Route declared in application.Routes
+// (or a single default Route named after the application), returning the
+// created/existing Routes so callers (e.g. finishSetupAndSetApplicationName,
+// `sd init --git-url`) can print their URL without a separate GetRoutes
+// round-trip.
+// Routes that already exist are fetched and returned as-is rather than
+// recreated.
+func CreateRouteTemplate(config *restclient.Config, application types.Application) ([]*routev1.Route, error) {
+	routeV1Client := getClient(config)
+
+	var result []*routev1.Route
+	for _, route := range BuildRoutes(application) {
+		existing, err := routeV1Client.Routes(application.Namespace).Get(route.Name, metav1.GetOptions{})
+		if err == nil {
+			log.Infof("'%s' Route already exists, skipping", route.Name)
+			result = append(result, existing)
+			continue
+		}
+		if !apierrors.IsNotFound(err) {
+			return nil, errors.Wrapf(err, "unable to retrieve existing Route '%s'", route.Name)
 		}
 
-		// Create the route ...
-		_, errRoute := routeV1Client.Routes(application.Namespace).Create(&route)
+		created, errRoute := routeV1Client.Routes(application.Namespace).Create(&route)
 		if errRoute != nil {
-			log.Fatal("error creating route", errRoute.Error())
+			return nil, errors.Wrapf(errRoute, "unable to create Route '%s'", route.Name)
 		}
+		result = append(result, created)
 	}
+	return result, nil
 }
 
 func getClient(config *restclient.Config) *routeclientsetv1.RouteV1Client {
+	if err := cfg.RequireServedGroupVersion(config, routev1.SchemeGroupVersion.String(), "Route"); err != nil {
+		log.Fatal(err.Error())
+	}
+
 	routeV1Client, errrouteclientsetv1 := routeclientsetv1.NewForConfig(config)
 	if errrouteclientsetv1 != nil {
 		log.Fatal("error creating route Clientset", errrouteclientsetv1.Error())
@@ -47,11 +131,59 @@ func getClient(config *restclient.Config) *routeclientsetv1.RouteV1Client {
 	return routeV1Client
 }
 
+// GetRoute fetches the application's live Route by name, or an error if it
+// doesn't exist yet.
+func GetRoute(config *restclient.Config, application types.Application, name string) (*routev1.Route, error) {
+	return getClient(config).Routes(application.Namespace).Get(name, metav1.GetOptions{})
+}
+
+// GetRoutes fetches every live Route declared for application (or the
+// single default one), skipping any that haven't been created yet.
+func GetRoutes(config *restclient.Config, application types.Application) ([]*routev1.Route, error) {
+	routeV1Client := getClient(config)
+
+	var routes []*routev1.Route
+	for _, route := range applicationRoutes(application) {
+		live, err := routeV1Client.Routes(application.Namespace).Get(route.Name, metav1.GetOptions{})
+		if apierrors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		routes = append(routes, live)
+	}
+	return routes, nil
+}
+
+// RouteURL returns the scheme + host of the first Ingress in route's status
+// that has been admitted, or an error if the Route hasn't been admitted by
+// any router yet.
+func RouteURL(route *routev1.Route) (string, error) {
+	scheme := "http"
+	if route.Spec.TLS != nil {
+		scheme = "https"
+	}
+
+	for _, ingress := range route.Status.Ingress {
+		for _, condition := range ingress.Conditions {
+			if condition.Type == routev1.RouteAdmitted && condition.Status == corev1.ConditionTrue {
+				return scheme + "://" + ingress.Host, nil
+			}
+		}
+	}
+
+	return "", errors.Errorf("route '%s' is not admitted yet", route.Name)
+}
+
+// DeleteRoute deletes every Route declared for application (or the single
+// default one), skipping any that don't exist.
 func DeleteRoute(config *restclient.Config, application types.Application) {
-	if oc.Exists("route", application.Name) {
-		// Create the route ...
-		errRoute := getClient(config).Routes(application.Namespace).Delete(application.Name, deleteOptions)
-		if errRoute != nil {
+	routeV1Client := getClient(config)
+
+	for _, route := range applicationRoutes(application) {
+		errRoute := routeV1Client.Routes(application.Namespace).Delete(route.Name, deleteOptions)
+		if errRoute != nil && !apierrors.IsNotFound(errRoute) {
 			log.Fatalf("Unable to delete Route: %s", errRoute.Error())
 		}
 	}
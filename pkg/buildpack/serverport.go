@@ -0,0 +1,112 @@
+package buildpack
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/ghodss/yaml"
+)
+
+const defaultServerPort = 8080
+
+// plainPropertyLine matches a single `key = value` (or `key=value`) entry
+// in a Java .properties-style file, once comments and blank lines have
+// already been skipped: whitespace around both key and value is
+// insignificant.
+var plainPropertyLine = regexp.MustCompile(`^([^=]+?)\s*=\s*(.*)$`)
+
+type springServerConfig struct {
+	Server struct {
+		Port int32 `json:"port"`
+	} `json:"server"`
+}
+
+// DetectServerPort looks for `server.port` in the project's Spring Boot
+// configuration under dir (application.properties, then application.yml,
+// then application.yaml) and returns it. It only looks when port is still
+// the repo default of 8080, so an explicit MANIFEST port always wins, and
+// it falls back to that default when none of the files declare the
+// property.
+func DetectServerPort(dir string, port int32) int32 {
+	if port != defaultServerPort {
+		return port
+	}
+
+	for _, candidate := range []string{"application.properties", "application.yml", "application.yaml"} {
+		path := filepath.Join(dir, "src", "main", "resources", candidate)
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			continue
+		}
+
+		if detected, ok := detectServerPort(candidate, data); ok {
+			return detected
+		}
+	}
+
+	return port
+}
+
+func detectServerPort(name string, data []byte) (int32, bool) {
+	if name == "application.properties" {
+		value, ok := parseProperties(name, data)["server.port"]
+		if !ok {
+			return 0, false
+		}
+
+		port, err := strconv.Atoi(value)
+		if err != nil {
+			log.Warnf("%s: server.port=%q is not a valid integer, ignoring", name, value)
+			return 0, false
+		}
+		return int32(port), true
+	}
+
+	var config springServerConfig
+	if err := yaml.Unmarshal(data, &config); err != nil || config.Server.Port == 0 {
+		return 0, false
+	}
+	return config.Server.Port, true
+}
+
+// parseProperties reads a Java .properties-style file's key/value entries,
+// skipping blank lines and '#'/'!'-prefixed comments (the two comment
+// markers the format allows), trimming whitespace around keys and values,
+// and unwrapping a value's surrounding quotes when it has matching ones. A
+// line that's left after that -- not blank, not a comment -- but still
+// doesn't contain '=' is malformed; it's logged with its 1-based line
+// number and skipped rather than aborting the whole file, since callers
+// only use this for best-effort detection with a sensible default to fall
+// back on.
+func parseProperties(name string, data []byte) map[string]string {
+	properties := map[string]string{}
+
+	for i, rawLine := range strings.Split(string(data), "\n") {
+		line := strings.TrimSpace(rawLine)
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, "!") {
+			continue
+		}
+
+		match := plainPropertyLine.FindStringSubmatch(line)
+		if match == nil {
+			log.Warnf("%s:%d: malformed property entry %q, ignoring", name, i+1, rawLine)
+			continue
+		}
+
+		key := strings.TrimSpace(match[1])
+		value := strings.TrimSpace(match[2])
+		if len(value) >= 2 {
+			if (value[0] == '"' && value[len(value)-1] == '"') || (value[0] == '\'' && value[len(value)-1] == '\'') {
+				value = value[1 : len(value)-1]
+			}
+		}
+		properties[key] = value
+	}
+
+	return properties
+}
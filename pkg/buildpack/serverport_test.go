@@ -0,0 +1,72 @@
+package buildpack_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/snowdrop/spring-boot-cloud-devex/pkg/buildpack"
+)
+
+func TestDetectServerPort(t *testing.T) {
+	cases := []struct {
+		name     string
+		fileName string
+		content  string
+		want     int32
+	}{
+		{"properties", "application.properties", "spring.application.name=demo\nserver.port=9001\n", 9001},
+		{"properties with comments and blank lines", "application.properties", "# this is a comment\n! so is this\n\nspring.application.name=demo\n\nserver.port = 9003\n", 9003},
+		{"properties with quoted value", "application.properties", "server.port = \"9004\"\n", 9004},
+		{"properties with malformed line", "application.properties", "this line has no equals sign\nserver.port=9005\n", 9005},
+		{"yaml", "application.yml", "server:\n  port: 9002\n", 9002},
+		{"absent", "", "", 8080},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			dir, err := ioutil.TempDir("", "serverport-test-")
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer os.RemoveAll(dir)
+
+			if c.fileName != "" {
+				resourcesDir := filepath.Join(dir, "src", "main", "resources")
+				if err := os.MkdirAll(resourcesDir, 0755); err != nil {
+					t.Fatal(err)
+				}
+				if err := ioutil.WriteFile(filepath.Join(resourcesDir, c.fileName), []byte(c.content), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			got := buildpack.DetectServerPort(dir, 8080)
+			if got != c.want {
+				t.Errorf("DetectServerPort() = %d, want %d", got, c.want)
+			}
+		})
+	}
+}
+
+func TestDetectServerPortDoesNotOverrideExplicitPort(t *testing.T) {
+	dir, err := ioutil.TempDir("", "serverport-test-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	resourcesDir := filepath.Join(dir, "src", "main", "resources")
+	if err := os.MkdirAll(resourcesDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(resourcesDir, "application.properties"), []byte("server.port=9001\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	got := buildpack.DetectServerPort(dir, 9090)
+	if got != 9090 {
+		t.Errorf("DetectServerPort() = %d, want explicit 9090 to win", got)
+	}
+}
@@ -7,38 +7,47 @@ import (
 
 	appsv1 "github.com/openshift/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"github.com/snowdrop/spring-boot-cloud-devex/pkg/buildpack/types"
-	"github.com/snowdrop/spring-boot-cloud-devex/pkg/common/oc"
 	"strings"
 )
 
+// BuildService returns the Service that would be created for application,
+// without touching the cluster.
+func BuildService(application types.Application) corev1.Service {
+	tName := strings.Join([]string{builderPath, "service"}, "/")
+	var b = ParseTemplate(tName, application)
+
+	svc := corev1.Service{}
+	errYamlParsing := yaml.Unmarshal(b.Bytes(), &svc)
+	if errYamlParsing != nil {
+		panic(errYamlParsing)
+	}
+	svc.Labels = MergeLabels(application.Labels, svc.Labels)
+	return svc
+}
+
 func CreateServiceTemplate(clientset *kubernetes.Clientset, dc *appsv1.DeploymentConfig, application types.Application) {
-	if oc.Exists("svc", application.Name) {
+	services := clientset.CoreV1().Services(application.Namespace)
+
+	if _, err := services.Get(application.Name, metav1.GetOptions{}); err == nil {
 		log.Infof("'%s' Service already exists, skipping", application.Name)
-	} else {
-		// Parse Service Template
-		tName := strings.Join([]string{builderPath, "service"}, "/")
-		var b = ParseTemplate(tName, application)
-
-		// Create Service struct using the generated Service string
-		svc := corev1.Service{}
-		errYamlParsing := yaml.Unmarshal(b.Bytes(), &svc)
-		if errYamlParsing != nil {
-			panic(errYamlParsing)
-		}
-		_, errService := clientset.CoreV1().Services(application.Namespace).Create(&svc)
-		if errService != nil {
-			log.Fatalf("Unable to create Service: %s", errService.Error())
-		}
+		return
+	} else if !apierrors.IsNotFound(err) {
+		log.Fatalf("Unable to check for an existing Service: %s", err.Error())
+	}
+
+	svc := BuildService(application)
+	if _, err := services.Create(&svc); err != nil {
+		log.Fatalf("Unable to create Service: %s", err.Error())
 	}
 }
 
 func DeleteService(clientset *kubernetes.Clientset, application types.Application) {
-	if oc.Exists("svc", application.Name) {
-		errService := clientset.CoreV1().Services(application.Namespace).Delete(application.Name, deleteOptions)
-		if errService != nil {
-			log.Fatalf("Unable to delete Service: %s", errService.Error())
-		}
+	err := clientset.CoreV1().Services(application.Namespace).Delete(application.Name, deleteOptions)
+	if err != nil && !apierrors.IsNotFound(err) {
+		log.Fatalf("Unable to delete Service: %s", err.Error())
 	}
 }
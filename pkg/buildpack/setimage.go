@@ -0,0 +1,100 @@
+package buildpack
+
+import (
+	"strings"
+
+	"github.com/pkg/errors"
+
+	appsv1 "github.com/openshift/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	restclient "k8s.io/client-go/rest"
+	"k8s.io/client-go/util/retry"
+
+	"github.com/snowdrop/spring-boot-cloud-devex/pkg/buildpack/types"
+)
+
+// SetImage points application's DeploymentConfig at an image produced
+// out-of-band (e.g. by a CI pipeline) instead of the S2I/Git build output:
+// it tags imageRef into the application's own ImageStreamTag, waits for the
+// import to resolve, then patches the DC's container to run it and to
+// watch that tag (rather than the S2I output) for future rollouts.
+func SetImage(config *restclient.Config, application types.Application, imageRef string) error {
+	if strings.TrimSpace(imageRef) == "" || strings.ContainsAny(imageRef, " \t\n") {
+		return errors.Errorf("'%s' is not a valid image reference", imageRef)
+	}
+
+	tagName := application.Name + ":latest"
+	TagExternalImage(config, application.Namespace, imageRef, application.Name, "latest")
+
+	if err := WaitForImageStreamImport(config, application.Namespace, application.Name, "latest", imageStreamImportTimeout); err != nil {
+		return errors.Wrapf(err, "'%s' did not resolve", imageRef)
+	}
+
+	client := getAppsClient(config).DeploymentConfigs(application.Namespace)
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		dc, err := client.Get(application.Name, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+
+		found := false
+		for i := range dc.Spec.Template.Spec.Containers {
+			container := &dc.Spec.Template.Spec.Containers[i]
+			if container.Name != application.Name {
+				continue
+			}
+			container.Image = imageRef
+			found = true
+		}
+		if !found {
+			return errors.Errorf("DeploymentConfig has no container named '%s'", application.Name)
+		}
+
+		dc.Spec.Triggers = retargetImageChangeTrigger(dc.Spec.Triggers, application.Name, tagName)
+
+		_, err = client.Update(dc)
+		return err
+	})
+	if err != nil {
+		return errors.Wrap(err, "unable to update DeploymentConfig")
+	}
+	return nil
+}
+
+// retargetImageChangeTrigger drops any ImageChange trigger watching
+// containerName (e.g. the shared dev-s2i stream SetImage is overriding) and
+// adds one watching fromImageStreamTag instead, so the DC keeps rolling out
+// automatically on future re-tags of --set-image's target.
+func retargetImageChangeTrigger(triggers []appsv1.DeploymentTriggerPolicy, containerName string, fromImageStreamTag string) []appsv1.DeploymentTriggerPolicy {
+	kept := make([]appsv1.DeploymentTriggerPolicy, 0, len(triggers)+1)
+	for _, trigger := range triggers {
+		if trigger.Type == appsv1.DeploymentTriggerOnImageChange &&
+			trigger.ImageChangeParams != nil &&
+			containsString(trigger.ImageChangeParams.ContainerNames, containerName) {
+			continue
+		}
+		kept = append(kept, trigger)
+	}
+
+	return append(kept, appsv1.DeploymentTriggerPolicy{
+		Type: appsv1.DeploymentTriggerOnImageChange,
+		ImageChangeParams: &appsv1.DeploymentTriggerImageChangeParams{
+			Automatic:      true,
+			ContainerNames: []string{containerName},
+			From: corev1.ObjectReference{
+				Kind: "ImageStreamTag",
+				Name: fromImageStreamTag,
+			},
+		},
+	})
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,46 @@
+package buildpack
+
+import (
+	"io/ioutil"
+
+	"github.com/ghodss/yaml"
+
+	"github.com/snowdrop/spring-boot-cloud-devex/pkg/buildpack/types"
+)
+
+// WriteSpec marshals application -- the fully-resolved, post-merge
+// configuration ParseManifest/ApplicationFromFlags would otherwise
+// recompute from flags and a MANIFEST -- to path as YAML, so it can be
+// committed as a reproducible artifact and fed straight back in via
+// ReadSpec, e.g. by `sd apply -f`. types.Application has no yaml/json
+// tags, so the keys it writes are the Go field names (e.g. "Name",
+// "ServiceAccount"), not the camelCase keys a hand-written MANIFEST uses
+// ("name", "serviceAccount") -- ReadSpec's json.Unmarshal matches both
+// case-insensitively, so this round-trips fine, but a WriteSpec file is
+// not interchangeable with a MANIFEST file edited by hand.
+func WriteSpec(path string, application types.Application) error {
+	encoded, err := yaml.Marshal(application)
+	if err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(path, encoded, 0644)
+}
+
+// ReadSpec reads path -- written by WriteSpec -- back into an Application,
+// with no flag merging, `extends`/profile resolution, or "auto" detection
+// applied: the file is already a complete, concrete configuration.
+func ReadSpec(path string) (types.Application, error) {
+	application := types.Application{}
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return application, err
+	}
+
+	if err := yaml.Unmarshal(data, &application); err != nil {
+		return application, err
+	}
+
+	return application, nil
+}
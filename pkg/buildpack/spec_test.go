@@ -0,0 +1,48 @@
+package buildpack_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/snowdrop/spring-boot-cloud-devex/pkg/buildpack"
+	"github.com/snowdrop/spring-boot-cloud-devex/pkg/buildpack/types"
+)
+
+func TestWriteSpecThenReadSpecRoundTrips(t *testing.T) {
+	dir, err := ioutil.TempDir("", "spec-test-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	application := types.NewApplication()
+	application.Name = "my-app"
+	application.Namespace = "my-ns"
+	application.Replicas = 2
+	application.Arch = "arm64"
+
+	specPath := filepath.Join(dir, "spec.yaml")
+	if err := buildpack.WriteSpec(specPath, application); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := buildpack.ReadSpec(specPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got.Name != "my-app" {
+		t.Errorf("Name = %q, want %q", got.Name, "my-app")
+	}
+	if got.Namespace != "my-ns" {
+		t.Errorf("Namespace = %q, want %q", got.Namespace, "my-ns")
+	}
+	if got.Replicas != 2 {
+		t.Errorf("Replicas = %d, want %d", got.Replicas, 2)
+	}
+	if got.Arch != "arm64" {
+		t.Errorf("Arch = %q, want %q", got.Arch, "arm64")
+	}
+}
@@ -0,0 +1,74 @@
+package buildpack
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	restclient "k8s.io/client-go/rest"
+
+	"github.com/snowdrop/spring-boot-cloud-devex/pkg/buildpack/types"
+)
+
+// RouteStatus is one Route's traffic-readiness: whether a router has
+// admitted it, and whether the Service it targets has at least one ready
+// endpoint. A Route can be admitted and still answer every request with a
+// 503 if its Service has no ready endpoints yet -- the readiness probe
+// BuildDeploymentConfig wires onto the container is what keeps a
+// not-yet-healthy pod out of the Service's endpoints in the first place, so
+// this is the "deployed but 503" case, not a routing misconfiguration.
+type RouteStatus struct {
+	Name     string
+	Admitted bool
+	Ready    bool
+	Message  string
+}
+
+// CheckRouteStatus reports RouteStatus for every Route declared for
+// application (or the single default one), so `sd status` can tell
+// "not deployed yet", "deployed but 503", and "deployed and serving" apart.
+func CheckRouteStatus(config *restclient.Config, clientset kubernetes.Interface, application types.Application) ([]RouteStatus, error) {
+	routes, err := GetRoutes(config, application)
+	if err != nil {
+		return nil, err
+	}
+
+	var statuses []RouteStatus
+	for _, route := range routes {
+		status := RouteStatus{Name: route.Name}
+
+		if _, err := RouteURL(route); err != nil {
+			status.Message = err.Error()
+			statuses = append(statuses, status)
+			continue
+		}
+		status.Admitted = true
+
+		endpoints, err := clientset.CoreV1().Endpoints(application.Namespace).Get(route.Spec.To.Name, metav1.GetOptions{})
+		if err != nil {
+			status.Message = fmt.Sprintf("unable to check Service '%s' endpoints: %s", route.Spec.To.Name, err.Error())
+			statuses = append(statuses, status)
+			continue
+		}
+
+		status.Ready = hasReadyAddress(endpoints)
+		if !status.Ready {
+			status.Message = "Route admitted but no ready endpoints"
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses, nil
+}
+
+// hasReadyAddress reports whether endpoints lists at least one ready
+// address. NotReadyAddresses doesn't count: those are exactly the pods a
+// Service withholds from traffic until their readiness probe passes.
+func hasReadyAddress(endpoints *corev1.Endpoints) bool {
+	for _, subset := range endpoints.Subsets {
+		if len(subset.Addresses) > 0 {
+			return true
+		}
+	}
+	return false
+}
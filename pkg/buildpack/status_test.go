@@ -0,0 +1,47 @@
+package buildpack
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestHasReadyAddress(t *testing.T) {
+	tests := []struct {
+		name      string
+		endpoints *corev1.Endpoints
+		want      bool
+	}{
+		{
+			name:      "no subsets",
+			endpoints: &corev1.Endpoints{},
+			want:      false,
+		},
+		{
+			name: "subset with only not-ready addresses",
+			endpoints: &corev1.Endpoints{
+				Subsets: []corev1.EndpointSubset{
+					{NotReadyAddresses: []corev1.EndpointAddress{{IP: "10.0.0.1"}}},
+				},
+			},
+			want: false,
+		},
+		{
+			name: "subset with a ready address",
+			endpoints: &corev1.Endpoints{
+				Subsets: []corev1.EndpointSubset{
+					{Addresses: []corev1.EndpointAddress{{IP: "10.0.0.1"}}},
+				},
+			},
+			want: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := hasReadyAddress(test.endpoints); got != test.want {
+				t.Errorf("hasReadyAddress() = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
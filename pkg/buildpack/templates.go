@@ -7,8 +7,6 @@ import (
 	log "github.com/sirupsen/logrus"
 	"os"
 	"text/template"
-
-	"github.com/snowdrop/spring-boot-cloud-devex/pkg/buildpack/types"
 )
 
 var (
@@ -62,8 +60,10 @@ func init() {
 	}
 }
 
-// Parse the file's template using the Application struct
-func ParseTemplate(tmpl string, cfg types.Application) bytes.Buffer {
+// ParseTemplate renders tmpl against cfg, which is usually a
+// types.Application but may be any template-shaped struct (e.g. a single
+// Route's rendering context).
+func ParseTemplate(tmpl string, cfg interface{}) bytes.Buffer {
 	// Create Template and parse it
 	var b bytes.Buffer
 	t := templates[tmpl]
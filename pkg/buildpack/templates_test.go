@@ -14,25 +14,28 @@ func TestServiceTemplate(t *testing.T) {
 
 	builderpath := "tmpl/java/"
 
-	const service = `apiVersion: v1
-kind: Service
-metadata:
-  name: service-test
-  labels:
-    app: service-test
-    name: service-test
-spec:
-  ports:
-  - port: 8080
-    protocol: TCP
-    targetPort: 8080
-  selector:
-    app: service-test
-    deploymentconfig: service-test`
+	const service = "apiVersion: v1\n" +
+		"kind: Service\n" +
+		"metadata:\n" +
+		"  name: service-test\n" +
+		"  labels:\n" +
+		"    app: service-test\n" +
+		"    name: service-test\n" +
+		"spec:\n" +
+		"  ports:\n" +
+		"  - name: http\n" +
+		"    port: 8080\n" +
+		"    protocol: TCP\n" +
+		"    targetPort: http\n" +
+		"  \n" +
+		"  selector:\n" +
+		"    app: service-test\n" +
+		"    deploymentconfig: service-test"
 
 	application := types.Application{
-		Name: "service-test",
-		Port: 8080,
+		Name:     "service-test",
+		Port:     8080,
+		PortName: "http",
 	}
 
 	// Get package full path
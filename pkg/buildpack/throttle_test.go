@@ -0,0 +1,24 @@
+package buildpack
+
+import (
+	"strings"
+	"testing"
+
+	"golang.org/x/time/rate"
+)
+
+func TestThrottledReaderCapsReadSizeToBurst(t *testing.T) {
+	r := &throttledReader{
+		r:       strings.NewReader("0123456789"),
+		limiter: rate.NewLimiter(rate.Limit(1000), 4),
+	}
+
+	buf := make([]byte, 10)
+	n, err := r.Read(buf)
+	if err != nil {
+		t.Fatalf("Read returned an error: %s", err.Error())
+	}
+	if n != 4 {
+		t.Errorf("Read() = %d bytes, want 4 (the limiter's burst size)", n)
+	}
+}
@@ -2,18 +2,203 @@ package types
 
 import "github.com/pkg/errors"
 
+// Supported values for the Application's BuildTool field.
+const (
+	BuildToolMaven  = "maven"
+	BuildToolGradle = "gradle"
+	BuildToolAuto   = "auto"
+)
+
+// Supported values for the Application's ProbePreset field.
+const (
+	ProbePresetSpringBoot2 = "springboot2"
+	ProbePresetSpringBoot3 = "springboot3"
+	ProbePresetLegacy      = "legacy"
+	ProbePresetAuto        = "auto"
+)
+
 type Application struct {
-	Name            string
-	Version         string
-	Namespace       string
-	Replica         int
-	Cpu             string `default:"100m"`
-	Memory          string `default:"250Mi"`
-	Port            int32  `default:"8080"`
-	Image           Image
-	SupervisordName string
-	Env             []Env
-	Services        []Service
+	Name               string
+	Version            string
+	Namespace          string
+	Replicas           int
+	Cpu                string `default:"100m"`
+	Memory             string `default:"250Mi"`
+	Port               int32  `default:"8080"`
+	PortName           string `default:"http"`
+	ManagementPort     int32  `default:"0"`
+	ManagementPortName string `default:"management"`
+	Image              Image
+	SupervisordName    string
+	SupervisordPort    int32 `default:"9001"`
+	BuildTool          string `default:"auto"`
+	// ProbePreset selects the readiness/liveness probe paths
+	// BuildDeploymentConfig renders: ProbePresetSpringBoot2 (the single
+	// combined /actuator/health endpoint), ProbePresetSpringBoot3 (the
+	// split health groups Boot 2.3+ added and Boot 3's Kubernetes docs
+	// recommend), ProbePresetLegacy (the pre-Actuator-2 "/health"
+	// endpoint), or ProbePresetAuto to detect the generation from
+	// pom.xml/build.gradle. Empty behaves like ProbePresetSpringBoot2, the
+	// generation every existing MANIFEST already assumed.
+	ProbePreset string `default:"springboot2"`
+	ServiceAccount     string
+	Strategy           string `default:"Rolling"`
+	Env                []Env
+	Services           []Service
+	HostAliases        []HostAlias
+	DNSConfig          DNSConfig
+	SecurityContext    SecurityContext
+	ExtraManifests     []string
+	DevImage           string
+	Routes             []Route
+	Labels             map[string]string
+	StartCommand       string
+	// Arch selects which architecture's image variant CreateDefaultImageStreams
+	// uses (see buildpack.ImageRepoForArch); "amd64"/"arm64". Empty means
+	// Setup() will detect it from a cluster node instead.
+	Arch string
+	// MountSource, when false, drops the supervisord init container and
+	// shared-data volume from the DC and lets the container run its image's
+	// own entrypoint as-is, for image-only dev pods that don't sync local
+	// source. push/compile/run all depend on supervisord being present and
+	// refuse to run in this mode.
+	MountSource bool `default:"true"`
+	Lifecycle   Lifecycle
+	// GitURL, when set, switches the application to the Git-source build
+	// strategy: an S2I BuildConfig builds the image from this repository
+	// instead of syncing local files, and the DC watches the BuildConfig's
+	// output ImageStreamTag for rollouts. It's mutually exclusive with the
+	// supervisord source-sync strategy MountSource/DevImage control.
+	GitURL string
+	// GitRef is the branch, tag, or commit BuildConfig's Git source checks
+	// out. Empty means the repository's default branch.
+	GitRef string
+	// MavenSettings, when set, is the local path to a settings.xml mounted
+	// into the dev pod as the "maven-settings" ConfigMap, so a corporate
+	// mirror/proxy/credentials declared there are used for the in-pod build
+	// instead of Maven Central.
+	MavenSettings string
+	// ContainerName overrides the DC's main container name, which is
+	// otherwise the application name. logs/exec/debug all resolve their
+	// default container the same way, so they keep working against a
+	// renamed container (e.g. to match a script that expects a fixed name,
+	// or to tell it apart in a multi-container pod). Must be a valid RFC
+	// 1123 DNS label.
+	ContainerName string
+	// ImageLookupLocal sets the generated ImageStreams' lookupPolicy.local,
+	// so DC image triggers resolve "dev-s2i:latest"-style references against
+	// the internal registry instead of requiring a fully-qualified pull
+	// spec. Defaults to true; some DC configurations fail to resolve the
+	// ImageStreamTag without it.
+	ImageLookupLocal bool `default:"true"`
+	// Incremental enables the S2I incremental build flag for the Git-source
+	// and binary-source BuildConfigs: the builder reuses artifacts saved from
+	// the application's previous build output image instead of starting from
+	// scratch, speeding up rebuilds of large apps. It has no effect on the
+	// supervisord source-sync strategy (MountSource), which never runs an S2I
+	// build in the first place. `sd build --clean` turns it off without
+	// having to edit the MANIFEST, but only while the BuildConfig is first
+	// created; an already-existing BuildConfig keeps its setting.
+	Incremental bool
+	// PVCName, when set, makes the DC mount this already-existing PVC
+	// instead of the "m2-data" one CreatePVC provisions, for teams that
+	// share a single pre-provisioned m2 cache PVC across applications.
+	// Setup() validates it exists and has a compatible access mode instead
+	// of creating it, and `sd clean --keep-pvc` defaults to true when it's
+	// set, since other applications may still be using it.
+	PVCName string
+	// RestartExtensions overrides buildpack.DefaultRestartExtensions, the set
+	// of file extensions `sd push --auto-restart` treats as requiring a
+	// supervisord restart to take effect. Empty means the default applies;
+	// everything else (templates, static assets) is assumed to be something
+	// Spring DevTools can hot-reload on its own.
+	RestartExtensions []string
+	// RouteDomain, when set, makes BuildRoute synthesize "<name>.<RouteDomain>"
+	// as a Route's host whenever the MANIFEST doesn't give it one explicitly,
+	// instead of leaving it to the cluster router's own default subdomain.
+	// Setup() fills it in from ~/.sd/clusters.yaml when the MANIFEST leaves
+	// it empty.
+	RouteDomain string
+	// Registry, when set, replaces the registry host of the default
+	// dev-s2i/supervisord images (e.g. "quay.io"), for clusters that mirror
+	// or proxy images internally. Setup() fills it in from
+	// ~/.sd/clusters.yaml when the MANIFEST leaves it empty.
+	Registry string
+	// PVCStorageClass, when set, is the storage class requested for the m2
+	// cache PVC BuildPVC creates, instead of the cluster's default storage
+	// class. Setup() fills it in from ~/.sd/clusters.yaml when the MANIFEST
+	// leaves it empty. Has no effect when PVCName is set, since an existing
+	// PVC's storage class can't be changed after the fact.
+	PVCStorageClass string
+	// PodAnnotations are applied to the DC's pod template, for policy
+	// annotations (Istio sidecar injection, backup policies, ...) that don't
+	// belong on the DC itself. `sd init --annotations-from-file` merges a
+	// file on top of whatever's declared here, so environment-specific
+	// policy doesn't have to live in the committed MANIFEST.
+	PodAnnotations map[string]string
+	// HPA declares a HorizontalPodAutoscaler tracking the DC's CPU usage.
+	// Enabled by setting MaxReplicas > 0; left at its zero value, no
+	// autoscaler is created. See the HPA type for the defaults MinReplicas
+	// and TargetCPUPercentage fall back to when left unset.
+	HPA HPA
+}
+
+// HPA holds the MANIFEST's `hpa:` section. It mirrors
+// autoscalingv1.HorizontalPodAutoscalerSpec closely enough that BuildHPA can
+// translate it directly, rather than introducing its own vocabulary.
+type HPA struct {
+	// MinReplicas defaults to 1 when left at 0.
+	MinReplicas int32
+	// MaxReplicas enables the HPA when > 0; 0 (the zero value) means no
+	// HorizontalPodAutoscaler is created at all.
+	MaxReplicas int32
+	// TargetCPUPercentage defaults to 80 when left at 0.
+	TargetCPUPercentage int32
+}
+
+// Lifecycle holds the container lifecycle hooks the MANIFEST can declare.
+type Lifecycle struct {
+	// PreStop, when set, overrides BuildDeploymentConfig's default preStop
+	// hook outright. When left empty and ManagementPort is set (Actuator is
+	// in play), BuildDeploymentConfig defaults it to a graceful Actuator
+	// shutdown call so a rolling update or `sd clean` doesn't drop
+	// in-flight requests.
+	PreStop []string
+}
+
+// Route describes one Route rendered for the application. When
+// Application.Routes is empty, CreateRouteTemplate falls back to a single
+// default Route named after the application, exposing its main port.
+type Route struct {
+	Name       string
+	Host       string
+	Path       string
+	TargetPort string
+	TLS        bool
+}
+
+// SecurityContext controls the pod/container security settings rendered
+// into the DC. Its default, as set by NewApplication, satisfies the
+// restricted-v2 SCC/PSA: run as a non-root, cluster-assigned UID with all
+// capabilities dropped.
+type SecurityContext struct {
+	RunAsNonRoot     bool `default:"true"`
+	RunAsUser        int64
+	FSGroup          int64
+	DropCapabilities []string
+}
+
+// HostAlias is injected as an entry in the dev pod's hosts file, for
+// reaching services that aren't resolvable via cluster DNS.
+type HostAlias struct {
+	IP        string
+	Hostnames []string
+}
+
+// DNSConfig holds DNS parameters appended to the dev pod's DNS policy.
+type DNSConfig struct {
+	Nameservers []string
+	Searches    []string
 }
 
 func (app *Application) GetService(name string) (Service, error) {
@@ -64,7 +249,14 @@ type Env struct {
 }
 
 type Image struct {
-	Name           string
+	Name string
+	// AnnotationCmds, when true, stamps the ImageStreamTag's "cmds"
+	// annotation with the supervisord compile/run program names derived
+	// from Application.BuildTool (or Application.StartCommand, if set),
+	// purely as documentation for anyone inspecting the ImageStream with
+	// `oc describe`. It has no effect on what the DC actually runs; that's
+	// always driven by the same value, passed directly to
+	// BuildDeploymentConfig, so the two can't drift apart.
 	AnnotationCmds bool
 	Repo           string
 	Tag            string
@@ -73,11 +265,22 @@ type Image struct {
 
 func NewApplication() Application {
 	return Application{
-		Version:         "1.0",
-		Cpu:             "100m",
-		Memory:          "250Mi",
-		Replica:         1,
-		Port:            8080,
-		SupervisordName: "copy-supervisord",
+		Version:          "1.0",
+		Cpu:              "100m",
+		Memory:           "250Mi",
+		Replicas:         1,
+		Port:             8080,
+		PortName:         "http",
+		SupervisordName:  "copy-supervisord",
+		SupervisordPort:  9001,
+		MountSource:      true,
+		BuildTool:        BuildToolAuto,
+		ProbePreset:      ProbePresetSpringBoot2,
+		Strategy:         "Rolling",
+		ImageLookupLocal: true,
+		SecurityContext: SecurityContext{
+			RunAsNonRoot:     true,
+			DropCapabilities: []string{"ALL"},
+		},
 	}
 }
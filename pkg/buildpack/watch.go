@@ -0,0 +1,77 @@
+package buildpack
+
+import (
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// watchIgnoredDirs are skipped when polling for source changes, since their
+// churn (build output, VCS metadata) has nothing to do with a developer's
+// edits and would otherwise trigger a rebuild loop on every compile.
+var watchIgnoredDirs = map[string]bool{
+	".git":   true,
+	"target": true,
+	"build":  true,
+}
+
+// WatchSource polls root for file changes every pollInterval and sends on
+// the returned channel, debounced so a burst of saves (e.g. an IDE writing
+// several files for one edit) triggers a single rebuild instead of one per
+// file. It stops and closes the channel once stop is closed.
+func WatchSource(root string, stop <-chan struct{}, pollInterval time.Duration, debounce time.Duration) <-chan struct{} {
+	changed := make(chan struct{})
+
+	go func() {
+		defer close(changed)
+
+		lastModified := latestModTime(root)
+		var pending <-chan time.Time
+
+		ticker := time.NewTicker(pollInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+				if modified := latestModTime(root); modified.After(lastModified) {
+					lastModified = modified
+					pending = time.After(debounce)
+				}
+			case <-pending:
+				pending = nil
+				select {
+				case changed <- struct{}{}:
+				case <-stop:
+					return
+				}
+			}
+		}
+	}()
+
+	return changed
+}
+
+// latestModTime returns the most recent modification time of any file under
+// root, skipping watchIgnoredDirs.
+func latestModTime(root string) time.Time {
+	var latest time.Time
+	_ = filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return nil
+		}
+		if info.IsDir() {
+			if watchIgnoredDirs[info.Name()] {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if info.ModTime().After(latest) {
+			latest = info.ModTime()
+		}
+		return nil
+	})
+	return latest
+}
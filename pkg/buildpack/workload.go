@@ -0,0 +1,345 @@
+package buildpack
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+
+	appsv1 "github.com/openshift/api/apps/v1"
+	appsocpv1 "github.com/openshift/client-go/apps/clientset/versioned/typed/apps/v1"
+
+	corev1 "k8s.io/api/core/v1"
+
+	kappsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes"
+	appstypedv1 "k8s.io/client-go/kubernetes/typed/apps/v1"
+	restclient "k8s.io/client-go/rest"
+	"k8s.io/client-go/util/retry"
+
+	"github.com/snowdrop/spring-boot-cloud-devex/pkg/buildpack/types"
+)
+
+// These mirror the annotations OpenShift's deployer pod stamps onto the
+// ReplicationController it creates for each DeploymentConfig rollout ("oc
+// rollout status" reads the same ones); the vendored client doesn't expose
+// them as typed constants.
+const (
+	deploymentConfigNameAnnotation   = "openshift.io/deployment-config.name"
+	deploymentVersionAnnotation      = "openshift.io/deployment-config.latest-version"
+	deploymentPhaseAnnotation        = "openshift.io/deployment.phase"
+	deploymentStatusReasonAnnotation = "openshift.io/deployment.status-reason"
+)
+
+// rolloutPollInterval is how often WaitForRollout re-checks rollout status.
+const rolloutPollInterval = 2 * time.Second
+
+// DefaultRolloutTimeout bounds how long WaitForRollout waits by default,
+// matching the budget a rolling update of a small number of replicas
+// typically needs.
+const DefaultRolloutTimeout = 5 * time.Minute
+
+// ErrNotInitialized is returned by GetWorkload/ResolveApplicationName when no
+// DeploymentConfig or Deployment named after the application exists yet.
+// Callers that operate against an already-deployed application (rather than
+// lazily provisioning one, the way Setup() does) should catch it and tell
+// the user to run 'sd init' first instead of surfacing the underlying
+// "not found" API error.
+var ErrNotInitialized = errors.New("not initialized: run 'sd init' first")
+
+// GetNamesByLabel returns the names of the DeploymentConfigs in namespace
+// matching labelName=labelValue, the client-go equivalent of
+// `oc get dc -l labelName=labelValue -o jsonpath=...`.
+func GetNamesByLabel(config *restclient.Config, namespace string, labelName string, labelValue string) ([]string, error) {
+	list, err := getAppsClient(config).DeploymentConfigs(namespace).List(metav1.ListOptions{
+		LabelSelector: labelName + "=" + labelValue,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(list.Items))
+	for _, dc := range list.Items {
+		names = append(names, dc.Name)
+	}
+	return names, nil
+}
+
+// ResolveApplicationName looks up the application name from the discovery
+// label, without creating anything if none is found -- unlike
+// finishSetupAndSetApplicationName, which provisions a new DC when no
+// labeled one exists yet. It's for commands that only make sense against an
+// already-deployed application.
+func ResolveApplicationName(config *restclient.Config, namespace string, labelName string, labelValue string) (string, error) {
+	return selectApplicationName(GetNamesByLabel(config, namespace, labelName, labelValue))
+}
+
+// selectApplicationName is ResolveApplicationName's pure decision logic,
+// split out so it's table-driven testable without a live cluster.
+func selectApplicationName(names []string, err error) (string, error) {
+	if err != nil {
+		return "", err
+	}
+	if len(names) == 0 {
+		return "", ErrNotInitialized
+	}
+	return names[0], nil
+}
+
+// Workload is the subset of rollout/scale operations a deployed application
+// needs, implemented once against OpenShift's DeploymentConfig and once
+// against a vanilla apps/v1 Deployment, so callers don't need their own
+// branch per platform. GetWorkload picks the right implementation.
+type Workload interface {
+	// Image returns the application container's current image.
+	Image() (string, error)
+	// SetImage updates the application container's image and rolls the
+	// change out.
+	SetImage(image string) error
+	// Scale sets the desired replica count.
+	Scale(replicas int32) error
+	// WaitForRollout blocks until the rollout triggered by the most recent
+	// SetImage/Scale call finishes, or returns a descriptive error if it
+	// fails or timeout elapses first.
+	WaitForRollout(timeout time.Duration) error
+}
+
+type dcWorkload struct {
+	client      appsocpv1.DeploymentConfigInterface
+	clientset   kubernetes.Interface
+	application types.Application
+}
+
+func (w *dcWorkload) get() (*appsv1.DeploymentConfig, error) {
+	dc, err := w.client.Get(w.application.Name, metav1.GetOptions{})
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to retrieve DeploymentConfig")
+	}
+	return dc, nil
+}
+
+func (w *dcWorkload) Image() (string, error) {
+	dc, err := w.get()
+	if err != nil {
+		return "", err
+	}
+	for _, container := range dc.Spec.Template.Spec.Containers {
+		if container.Name == w.application.Name {
+			return container.Image, nil
+		}
+	}
+	return "", errors.Errorf("container '%s' not found in DeploymentConfig '%s'", w.application.Name, dc.Name)
+}
+
+func (w *dcWorkload) SetImage(image string) error {
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		dc, err := w.get()
+		if err != nil {
+			return err
+		}
+		for i := range dc.Spec.Template.Spec.Containers {
+			if dc.Spec.Template.Spec.Containers[i].Name == w.application.Name {
+				dc.Spec.Template.Spec.Containers[i].Image = image
+			}
+		}
+		_, err = w.client.Update(dc)
+		return err
+	})
+	if err != nil {
+		return errors.Wrap(err, "unable to update DeploymentConfig image")
+	}
+	return nil
+}
+
+func (w *dcWorkload) Scale(replicas int32) error {
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		dc, err := w.get()
+		if err != nil {
+			return err
+		}
+		dc.Spec.Replicas = replicas
+		_, err = w.client.Update(dc)
+		return err
+	})
+	if err != nil {
+		return errors.Wrap(err, "unable to scale DeploymentConfig")
+	}
+	return nil
+}
+
+// WaitForRollout polls the DeploymentConfig's latest ReplicationController
+// (the one the deployer pod creates for rollout at Status.LatestVersion)
+// until its "openshift.io/deployment.phase" annotation reaches "Complete",
+// the same annotation `oc rollout status` watches.
+func (w *dcWorkload) WaitForRollout(timeout time.Duration) error {
+	var lastPhase string
+	var lastReason string
+
+	err := wait.PollImmediate(rolloutPollInterval, timeout, func() (bool, error) {
+		dc, err := w.get()
+		if err != nil {
+			return false, err
+		}
+
+		rc, err := w.latestReplicationController(dc.Status.LatestVersion)
+		if err != nil || rc == nil {
+			return false, err
+		}
+
+		lastPhase = rc.Annotations[deploymentPhaseAnnotation]
+		lastReason = rc.Annotations[deploymentStatusReasonAnnotation]
+
+		switch lastPhase {
+		case "Complete":
+			return true, nil
+		case "Failed":
+			return false, errors.Errorf("rollout of '%s' failed: %s", w.application.Name, lastReason)
+		default:
+			return false, nil
+		}
+	})
+
+	if err == wait.ErrWaitTimeout {
+		return errors.Errorf("timed out after %s waiting for '%s' to roll out (last phase: %s)", timeout, w.application.Name, lastPhase)
+	}
+	return err
+}
+
+// latestReplicationController returns the ReplicationController for this
+// DeploymentConfig's rollout at latestVersion, or nil if the deployer
+// hasn't created it yet.
+func (w *dcWorkload) latestReplicationController(latestVersion int64) (*corev1.ReplicationController, error) {
+	list, err := w.clientset.CoreV1().ReplicationControllers(w.application.Namespace).List(metav1.ListOptions{
+		LabelSelector: deploymentConfigNameAnnotation + "=" + w.application.Name,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	target := strconv.FormatInt(latestVersion, 10)
+	for i := range list.Items {
+		if list.Items[i].Annotations[deploymentVersionAnnotation] == target {
+			return &list.Items[i], nil
+		}
+	}
+	return nil, nil
+}
+
+type deploymentWorkload struct {
+	client      appstypedv1.DeploymentInterface
+	application types.Application
+}
+
+func (w *deploymentWorkload) get() (*kappsv1.Deployment, error) {
+	d, err := w.client.Get(w.application.Name, metav1.GetOptions{})
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to retrieve Deployment")
+	}
+	return d, nil
+}
+
+func (w *deploymentWorkload) Image() (string, error) {
+	d, err := w.get()
+	if err != nil {
+		return "", err
+	}
+	for _, container := range d.Spec.Template.Spec.Containers {
+		if container.Name == w.application.Name {
+			return container.Image, nil
+		}
+	}
+	return "", errors.Errorf("container '%s' not found in Deployment '%s'", w.application.Name, d.Name)
+}
+
+func (w *deploymentWorkload) SetImage(image string) error {
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		d, err := w.get()
+		if err != nil {
+			return err
+		}
+		for i := range d.Spec.Template.Spec.Containers {
+			if d.Spec.Template.Spec.Containers[i].Name == w.application.Name {
+				d.Spec.Template.Spec.Containers[i].Image = image
+			}
+		}
+		_, err = w.client.Update(d)
+		return err
+	})
+	if err != nil {
+		return errors.Wrap(err, "unable to update Deployment image")
+	}
+	return nil
+}
+
+func (w *deploymentWorkload) Scale(replicas int32) error {
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		d, err := w.get()
+		if err != nil {
+			return err
+		}
+		d.Spec.Replicas = &replicas
+		_, err = w.client.Update(d)
+		return err
+	})
+	if err != nil {
+		return errors.Wrap(err, "unable to scale Deployment")
+	}
+	return nil
+}
+
+// WaitForRollout polls the Deployment's status the same way `kubectl
+// rollout status` does: every replica updated to the new template,
+// available, and accounted for at the latest observed generation.
+func (w *deploymentWorkload) WaitForRollout(timeout time.Duration) error {
+	var status string
+
+	err := wait.PollImmediate(rolloutPollInterval, timeout, func() (bool, error) {
+		d, err := w.get()
+		if err != nil {
+			return false, err
+		}
+
+		for _, condition := range d.Status.Conditions {
+			if condition.Type == kappsv1.DeploymentProgressing && condition.Reason == "ProgressDeadlineExceeded" {
+				return false, errors.Errorf("rollout of '%s' failed: %s", w.application.Name, condition.Message)
+			}
+		}
+
+		desired := int32(1)
+		if d.Spec.Replicas != nil {
+			desired = *d.Spec.Replicas
+		}
+		status = fmt.Sprintf("%d/%d replicas updated", d.Status.UpdatedReplicas, desired)
+
+		if d.Status.ObservedGeneration >= d.Generation && d.Status.UpdatedReplicas == desired &&
+			d.Status.Replicas == desired && d.Status.AvailableReplicas == desired {
+			return true, nil
+		}
+		return false, nil
+	})
+
+	if err == wait.ErrWaitTimeout {
+		return errors.Errorf("timed out after %s waiting for '%s' to roll out (%s)", timeout, w.application.Name, status)
+	}
+	return err
+}
+
+// GetWorkload returns the Workload implementation for whichever platform
+// application is actually running on: a DeploymentConfig on OpenShift, or a
+// Deployment everywhere else. It probes for a DeploymentConfig first, since
+// every dev pod this tool creates today is still OpenShift-only (`sd init`
+// only ever builds a DeploymentConfig); the Deployment branch exists for
+// applications created outside this tool on a plain Kubernetes cluster.
+func GetWorkload(config *restclient.Config, clientset kubernetes.Interface, application types.Application) (Workload, error) {
+	dcClient := getAppsClient(config).DeploymentConfigs(application.Namespace)
+	if _, err := dcClient.Get(application.Name, metav1.GetOptions{}); err == nil {
+		return &dcWorkload{client: dcClient, clientset: clientset, application: application}, nil
+	}
+	if _, err := clientset.AppsV1().Deployments(application.Namespace).Get(application.Name, metav1.GetOptions{}); err == nil {
+		return &deploymentWorkload{client: clientset.AppsV1().Deployments(application.Namespace), application: application}, nil
+	}
+	return nil, ErrNotInitialized
+}
@@ -0,0 +1,60 @@
+package buildpack
+
+import (
+	"testing"
+
+	"github.com/pkg/errors"
+)
+
+func TestSelectApplicationName(t *testing.T) {
+	lookupErr := errors.New("oc: not logged in")
+
+	tests := []struct {
+		name    string
+		names   []string
+		err     error
+		want    string
+		wantErr error
+	}{
+		{
+			name:  "single match is used",
+			names: []string{"my-app"},
+			want:  "my-app",
+		},
+		{
+			name:  "first of several matches is used",
+			names: []string{"my-app", "my-app-old"},
+			want:  "my-app",
+		},
+		{
+			name:    "no match is ErrNotInitialized",
+			names:   nil,
+			wantErr: ErrNotInitialized,
+		},
+		{
+			name:    "lookup error is propagated as-is",
+			err:     lookupErr,
+			wantErr: lookupErr,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := selectApplicationName(test.names, test.err)
+
+			if test.wantErr != nil {
+				if err != test.wantErr {
+					t.Fatalf("selectApplicationName() error = %v, want %v", err, test.wantErr)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("selectApplicationName() returned an unexpected error: %s", err.Error())
+			}
+			if got != test.want {
+				t.Errorf("selectApplicationName() = %q, want %q", got, test.want)
+			}
+		})
+	}
+}
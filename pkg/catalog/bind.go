@@ -14,7 +14,6 @@ import (
 
 	"github.com/pkg/errors"
 	"github.com/snowdrop/spring-boot-cloud-devex/pkg/buildpack/types"
-	"github.com/snowdrop/spring-boot-cloud-devex/pkg/common/oc"
 	"k8s.io/apimachinery/pkg/util/uuid"
 )
 
@@ -64,15 +63,11 @@ func MountSecretAsEnvFrom(config *restclient.Config, application types.Applicati
 	deploymentConfigV1client := getAppsClient(config)
 	deploymentConfigs := deploymentConfigV1client.DeploymentConfigs(application.Namespace)
 
-	var dc *appsv1.DeploymentConfig
-	var err error
-	if oc.Exists("dc", application.Name) {
-		dc, err = deploymentConfigs.Get(application.Name, metav1.GetOptions{})
-		log.Infof("'%s' DeploymentConfig exists, got it", application.Name)
-	}
+	dc, err := deploymentConfigs.Get(application.Name, metav1.GetOptions{})
 	if err != nil {
 		log.Fatalf("DeploymentConfig does not exist : %s", err.Error())
 	}
+	log.Infof("'%s' DeploymentConfig exists, got it", application.Name)
 
 	// Add the Secret as EnvVar to the container
 	dc.Spec.Template.Spec.Containers[0].EnvFrom = addSecretAsEnvFromSource(secretName)
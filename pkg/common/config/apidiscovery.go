@@ -0,0 +1,30 @@
+package config
+
+import (
+	"github.com/pkg/errors"
+	"k8s.io/client-go/discovery"
+	restclient "k8s.io/client-go/rest"
+)
+
+// RequireServedGroupVersion queries the cluster's discovery API and fails
+// with a clear error if groupVersion (e.g. "apps.openshift.io/v1") isn't
+// served, instead of letting the first real request against a typed client
+// for a removed/not-yet-enabled API fail deep inside client-go with an
+// opaque "the server could not find the requested resource" error. kind is
+// used only to name the resource in the error message (e.g.
+// "DeploymentConfig").
+//
+// This matters across OpenShift cluster upgrades, where a previously-served
+// GroupVersion can be deprecated or removed outright.
+func RequireServedGroupVersion(restConfig *restclient.Config, groupVersion string, kind string) error {
+	discoveryClient, err := discovery.NewDiscoveryClientForConfig(restConfig)
+	if err != nil {
+		return errors.Wrap(err, "unable to create discovery client")
+	}
+
+	if _, err := discoveryClient.ServerResourcesForGroupVersion(groupVersion); err != nil {
+		return errors.Wrapf(err, "API '%s' required for %s is not served by this cluster", groupVersion, kind)
+	}
+
+	return nil
+}
@@ -0,0 +1,61 @@
+package config
+
+import (
+	"io/ioutil"
+	"os"
+
+	"github.com/ghodss/yaml"
+)
+
+const ClustersFile = "/.sd/clusters.yaml"
+
+// ClusterSettings is one cluster's entry in ~/.sd/clusters.yaml, keyed by
+// the cluster's API server URL (rest.Config.Host), so a developer working
+// across multiple clusters doesn't have to repeat cluster-specific flags on
+// every invocation.
+type ClusterSettings struct {
+	// RouteDomain, when set, is appended to an application/route name to
+	// synthesize a Route's host when the MANIFEST doesn't give it one
+	// explicitly, instead of leaving it to the cluster router's own default
+	// subdomain.
+	RouteDomain string `json:"routeDomain,omitempty"`
+	// Registry, when set, replaces the default images' registry host (e.g.
+	// "quay.io") for clusters that mirror or proxy images internally.
+	Registry string `json:"registry,omitempty"`
+	// StorageClass, when set, is the storage class requested for the m2
+	// cache PVC, instead of the cluster's default storage class.
+	StorageClass string `json:"storageClass,omitempty"`
+}
+
+// HomeClustersPath returns $HOME/.sd/clusters.yaml, mirroring
+// HomeKubePath's error handling when no home directory can be determined.
+func HomeClustersPath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return home + ClustersFile, nil
+}
+
+// LoadClusterSettings reads ~/.sd/clusters.yaml and returns the entry keyed
+// by serverURL (a cluster's resolved API server URL). A missing file,
+// unreadable YAML, or no matching entry all yield the zero value -- the
+// file is an optional convenience, not a requirement.
+func LoadClusterSettings(serverURL string) ClusterSettings {
+	path, err := HomeClustersPath()
+	if err != nil {
+		return ClusterSettings{}
+	}
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return ClusterSettings{}
+	}
+
+	var clusters map[string]ClusterSettings
+	if err := yaml.Unmarshal(data, &clusters); err != nil {
+		return ClusterSettings{}
+	}
+
+	return clusters[serverURL]
+}
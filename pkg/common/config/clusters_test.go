@@ -0,0 +1,73 @@
+package config_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/snowdrop/spring-boot-cloud-devex/pkg/common/config"
+)
+
+func withHome(t *testing.T, contents string) {
+	t.Helper()
+
+	dir, err := ioutil.TempDir("", "sd-clusters-test-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+
+	if err := os.MkdirAll(filepath.Join(dir, ".sd"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if contents != "" {
+		if err := ioutil.WriteFile(filepath.Join(dir, ".sd", "clusters.yaml"), []byte(contents), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	oldHome := os.Getenv("HOME")
+	os.Setenv("HOME", dir)
+	t.Cleanup(func() { os.Setenv("HOME", oldHome) })
+}
+
+func TestLoadClusterSettings(t *testing.T) {
+	withHome(t, ""+
+		"\"https://cluster-a.example.com:6443\":\n"+
+		"  routeDomain: apps.cluster-a.example.com\n"+
+		"  registry: registry.cluster-a.example.com\n"+
+		"  storageClass: fast-ssd\n")
+
+	got := config.LoadClusterSettings("https://cluster-a.example.com:6443")
+
+	if got.RouteDomain != "apps.cluster-a.example.com" {
+		t.Errorf("RouteDomain = %q, want %q", got.RouteDomain, "apps.cluster-a.example.com")
+	}
+	if got.Registry != "registry.cluster-a.example.com" {
+		t.Errorf("Registry = %q, want %q", got.Registry, "registry.cluster-a.example.com")
+	}
+	if got.StorageClass != "fast-ssd" {
+		t.Errorf("StorageClass = %q, want %q", got.StorageClass, "fast-ssd")
+	}
+}
+
+func TestLoadClusterSettingsUnknownCluster(t *testing.T) {
+	withHome(t, "\"https://cluster-a.example.com:6443\":\n  routeDomain: apps.cluster-a.example.com\n")
+
+	got := config.LoadClusterSettings("https://cluster-b.example.com:6443")
+
+	if got != (config.ClusterSettings{}) {
+		t.Errorf("LoadClusterSettings() for an unknown cluster = %+v, want the zero value", got)
+	}
+}
+
+func TestLoadClusterSettingsNoFile(t *testing.T) {
+	withHome(t, "")
+
+	got := config.LoadClusterSettings("https://cluster-a.example.com:6443")
+
+	if got != (config.ClusterSettings{}) {
+		t.Errorf("LoadClusterSettings() with no clusters.yaml = %+v, want the zero value", got)
+	}
+}
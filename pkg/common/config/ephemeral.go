@@ -0,0 +1,57 @@
+package config
+
+import (
+	"math/rand"
+	"os"
+	"strings"
+	"time"
+)
+
+// EphemeralSuffixEnvVars are checked, in order, for a CI-provided build
+// identifier to derive --ephemeral's namespace suffix from, so the same
+// suffix is produced at `sd push` and `sd clean` time within one CI job
+// without any extra state to pass between the two. The first one set wins.
+var EphemeralSuffixEnvVars = []string{"GITHUB_RUN_ID", "BUILD_NUMBER", "CI_JOB_ID", "CI_PIPELINE_ID"}
+
+const ephemeralSuffixAlphabet = "abcdefghijklmnopqrstuvwxyz0123456789"
+const randomEphemeralSuffixLength = 5
+
+// ResolveEphemeralSuffix returns the namespace suffix --ephemeral should
+// use: the first non-empty EphemeralSuffixEnvVars value, sanitized down to
+// what a namespace name allows, or a random
+// randomEphemeralSuffixLength-character suffix when none of them are set
+// (which only lasts for this process -- a later `sd clean` run needs
+// --namespace-suffix with the value this run logged).
+func ResolveEphemeralSuffix() string {
+	for _, name := range EphemeralSuffixEnvVars {
+		if value := os.Getenv(name); value != "" {
+			if sanitized := sanitizeNamespaceSuffix(value); sanitized != "" {
+				return sanitized
+			}
+		}
+	}
+	return randomEphemeralSuffix()
+}
+
+// sanitizeNamespaceSuffix lowercases value and strips everything but
+// RFC 1123 label-safe characters, since a CI build id isn't guaranteed to
+// already be a valid namespace name fragment (some systems mix in branch
+// names with slashes or underscores).
+func sanitizeNamespaceSuffix(value string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(value) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') || r == '-' {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+func randomEphemeralSuffix() string {
+	r := rand.New(rand.NewSource(time.Now().UnixNano()))
+	b := make([]byte, randomEphemeralSuffixLength)
+	for i := range b {
+		b[i] = ephemeralSuffixAlphabet[r.Intn(len(ephemeralSuffixAlphabet))]
+	}
+	return string(b)
+}
@@ -0,0 +1,45 @@
+package config_test
+
+import (
+	"os"
+	"testing"
+
+	"github.com/snowdrop/spring-boot-cloud-devex/pkg/common/config"
+)
+
+func TestResolveEphemeralSuffixFromEnv(t *testing.T) {
+	for _, name := range config.EphemeralSuffixEnvVars {
+		os.Unsetenv(name)
+	}
+	os.Setenv("BUILD_NUMBER", "PR-123/Merge")
+	defer os.Unsetenv("BUILD_NUMBER")
+
+	if got, want := config.ResolveEphemeralSuffix(), "pr-123merge"; got != want {
+		t.Errorf("ResolveEphemeralSuffix() = %q, want %q (sanitized BUILD_NUMBER)", got, want)
+	}
+}
+
+func TestResolveEphemeralSuffixEnvPrecedence(t *testing.T) {
+	for _, name := range config.EphemeralSuffixEnvVars {
+		os.Unsetenv(name)
+	}
+	os.Setenv("GITHUB_RUN_ID", "42")
+	os.Setenv("BUILD_NUMBER", "99")
+	defer os.Unsetenv("GITHUB_RUN_ID")
+	defer os.Unsetenv("BUILD_NUMBER")
+
+	if got, want := config.ResolveEphemeralSuffix(), "42"; got != want {
+		t.Errorf("ResolveEphemeralSuffix() = %q, want %q (GITHUB_RUN_ID takes priority)", got, want)
+	}
+}
+
+func TestResolveEphemeralSuffixRandomFallback(t *testing.T) {
+	for _, name := range config.EphemeralSuffixEnvVars {
+		os.Unsetenv(name)
+	}
+
+	suffix := config.ResolveEphemeralSuffix()
+	if len(suffix) != 5 {
+		t.Errorf("ResolveEphemeralSuffix() = %q, want a 5-character random suffix when no CI env var is set", suffix)
+	}
+}
@@ -0,0 +1,40 @@
+package config
+
+import (
+	"os/exec"
+
+	"github.com/pkg/errors"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+// CheckExecAuth verifies the exec-based credential plugin (e.g. an OIDC or
+// cloud IAM provider) configured for kubeConfigPath's current context, if
+// any. It reports a clear error when the plugin binary can't be found on
+// $PATH, rather than letting a later API call fail with an opaque
+// "executable not found" error deep inside client-go's transport.
+//
+// Returns a nil error when the current context doesn't use exec auth at
+// all; there's nothing to verify.
+func CheckExecAuth(kubeConfigPath string) error {
+	rawConfig, err := clientcmd.LoadFromFile(kubeConfigPath)
+	if err != nil {
+		return errors.Wrapf(err, "unable to read kubeconfig '%s'", kubeConfigPath)
+	}
+
+	context, ok := rawConfig.Contexts[rawConfig.CurrentContext]
+	if !ok {
+		return errors.Errorf("kubeconfig '%s' has no current context", kubeConfigPath)
+	}
+
+	authInfo, ok := rawConfig.AuthInfos[context.AuthInfo]
+	if !ok || authInfo.Exec == nil {
+		return nil
+	}
+
+	if _, err := exec.LookPath(authInfo.Exec.Command); err != nil {
+		return errors.Errorf("auth plugin '%s' (configured for context '%s') is not on $PATH: %s",
+			authInfo.Exec.Command, rawConfig.CurrentContext, err.Error())
+	}
+
+	return nil
+}
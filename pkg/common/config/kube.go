@@ -1,8 +1,9 @@
 package config
 
 import (
-	log "github.com/sirupsen/logrus"
-	"os/user"
+	"os"
+
+	"github.com/pkg/errors"
 )
 
 const (
@@ -18,10 +19,15 @@ func NewKube() *Kube {
 	return &Kube{}
 }
 
-func HomeKubePath() string {
-	usr, err := user.Current()
+// HomeKubePath returns $HOME/.kube/config, the default kubeconfig location.
+// It errors when no home directory can be determined (e.g. $HOME is unset,
+// as can happen in a minimal container), so callers can fall back to
+// KUBECONFIG or an in-cluster config instead of using a bogus path like
+// "/.kube/config".
+func HomeKubePath() (string, error) {
+	home, err := os.UserHomeDir()
 	if err != nil {
-		log.Debugf("Can't get current user:\n%v", err)
+		return "", errors.Wrap(err, "unable to determine home directory")
 	}
-	return usr.HomeDir + KUBECONFILE
+	return home + KUBECONFILE, nil
 }
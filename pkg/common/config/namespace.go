@@ -0,0 +1,71 @@
+package config
+
+import "github.com/pkg/errors"
+
+// NamespaceSources bundles every signal Setup() can use to pick a
+// namespace, so the precedence between them lives in one pure, testable
+// function instead of being threaded through cmd/root.go's control flow.
+type NamespaceSources struct {
+	// Explicit is an operator-supplied override (--namespace). Highest
+	// precedence: it always wins when set.
+	Explicit string
+
+	// Manifest is the MANIFEST's `namespace:` field.
+	Manifest string
+
+	// PreferKubeContext makes KubeContext take priority over OcProject
+	// (--namespace-from-context), instead of the default where OcProject
+	// wins.
+	PreferKubeContext bool
+
+	// OcProject is what `oc project -q` reported, and OcProjectErr any
+	// error encountered running it (e.g. the `oc` binary isn't on $PATH).
+	OcProject    string
+	OcProjectErr error
+
+	// KubeContext is the kubeconfig's current context's namespace, and
+	// KubeContextErr any error encountered reading it.
+	KubeContext    string
+	KubeContextErr error
+}
+
+// ResolveNamespace picks the namespace every subsequent API call should use,
+// from NamespaceSources, in precedence order:
+//
+//  1. Explicit (--namespace)
+//  2. Manifest (the MANIFEST's `namespace:` field)
+//  3. Whichever of OcProject/KubeContext PreferKubeContext selects,
+//     falling back to the other one if it's empty or errored
+//
+// It's a pure function of its inputs, so the precedence rules can be
+// table-driven tested without a live cluster or kubeconfig.
+func ResolveNamespace(sources NamespaceSources) (string, error) {
+	if sources.Explicit != "" {
+		return sources.Explicit, nil
+	}
+	if sources.Manifest != "" {
+		return sources.Manifest, nil
+	}
+
+	primary, primaryErr := sources.OcProject, sources.OcProjectErr
+	secondary, secondaryErr := sources.KubeContext, sources.KubeContextErr
+	if sources.PreferKubeContext {
+		primary, secondary = secondary, primary
+		primaryErr, secondaryErr = secondaryErr, primaryErr
+	}
+
+	if primaryErr == nil && primary != "" {
+		return primary, nil
+	}
+	if secondaryErr == nil && secondary != "" {
+		return secondary, nil
+	}
+
+	if primaryErr != nil {
+		return "", primaryErr
+	}
+	if secondaryErr != nil {
+		return "", secondaryErr
+	}
+	return "", errors.New("no namespace could be resolved: set --namespace, the MANIFEST's namespace field, or a namespace on the kubeconfig's current context")
+}
@@ -0,0 +1,115 @@
+package config_test
+
+import (
+	"testing"
+
+	"github.com/pkg/errors"
+
+	"github.com/snowdrop/spring-boot-cloud-devex/pkg/common/config"
+)
+
+func TestResolveNamespacePrecedence(t *testing.T) {
+	ocErr := errors.New("oc: not logged in")
+	kubeErr := errors.New("kubeconfig: no current context")
+
+	tests := []struct {
+		name    string
+		sources config.NamespaceSources
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "explicit wins over everything",
+			sources: config.NamespaceSources{
+				Explicit:    "from-flag",
+				Manifest:    "from-manifest",
+				OcProject:   "from-oc",
+				KubeContext: "from-kube-context",
+			},
+			want: "from-flag",
+		},
+		{
+			name: "manifest wins over oc project and kube context",
+			sources: config.NamespaceSources{
+				Manifest:    "from-manifest",
+				OcProject:   "from-oc",
+				KubeContext: "from-kube-context",
+			},
+			want: "from-manifest",
+		},
+		{
+			name: "oc project wins over kube context by default",
+			sources: config.NamespaceSources{
+				OcProject:   "from-oc",
+				KubeContext: "from-kube-context",
+			},
+			want: "from-oc",
+		},
+		{
+			name: "kube context preferred when PreferKubeContext is set",
+			sources: config.NamespaceSources{
+				PreferKubeContext: true,
+				OcProject:         "from-oc",
+				KubeContext:       "from-kube-context",
+			},
+			want: "from-kube-context",
+		},
+		{
+			name: "falls back to kube context when oc project is empty",
+			sources: config.NamespaceSources{
+				KubeContext: "from-kube-context",
+			},
+			want: "from-kube-context",
+		},
+		{
+			name: "falls back to kube context when oc project errored",
+			sources: config.NamespaceSources{
+				OcProjectErr: ocErr,
+				KubeContext:  "from-kube-context",
+			},
+			want: "from-kube-context",
+		},
+		{
+			name: "falls back to oc project when PreferKubeContext's kube context errored",
+			sources: config.NamespaceSources{
+				PreferKubeContext: true,
+				KubeContextErr:    kubeErr,
+				OcProject:         "from-oc",
+			},
+			want: "from-oc",
+		},
+		{
+			name: "no source resolved is an error",
+			sources: config.NamespaceSources{
+				OcProjectErr:   ocErr,
+				KubeContextErr: kubeErr,
+			},
+			wantErr: true,
+		},
+		{
+			name:    "zero value is an error",
+			sources: config.NamespaceSources{},
+			wantErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := config.ResolveNamespace(test.sources)
+
+			if test.wantErr {
+				if err == nil {
+					t.Fatalf("ResolveNamespace() = %q, nil, want an error", got)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("ResolveNamespace() returned an unexpected error: %s", err.Error())
+			}
+			if got != test.want {
+				t.Errorf("ResolveNamespace() = %q, want %q", got, test.want)
+			}
+		})
+	}
+}
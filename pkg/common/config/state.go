@@ -0,0 +1,53 @@
+package config
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+)
+
+const stateDir = ".sd"
+const stateFile = "config.json"
+
+// State is the cached result of a previous Setup(), used by --reuse-pod to
+// skip re-discovering the application name, namespace and pod on
+// back-to-back command invocations.
+type State struct {
+	ApplicationName string
+	Namespace       string
+	PodName         string
+}
+
+func statePath() string {
+	return stateDir + "/" + stateFile
+}
+
+// SaveState writes state to .sd/config.json, creating the directory if
+// needed.
+func SaveState(state State) error {
+	if err := os.MkdirAll(stateDir, 0755); err != nil {
+		return err
+	}
+
+	b, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(statePath(), b, 0644)
+}
+
+// LoadState reads the state written by a previous SaveState, if any.
+func LoadState() (State, bool) {
+	b, err := ioutil.ReadFile(statePath())
+	if err != nil {
+		return State{}, false
+	}
+
+	var state State
+	if err := json.Unmarshal(b, &state); err != nil {
+		return State{}, false
+	}
+
+	return state, true
+}
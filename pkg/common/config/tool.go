@@ -11,8 +11,30 @@ const (
 	SupervisordCtl = "ctl"
 	RunCmdName     = "run-java"
 	CompileCmdName = "compile-java"
+
+	GradleRunCmdName     = "run-gradle"
+	GradleCompileCmdName = "compile-gradle"
 )
 
+// CommandNames returns the supervisord program names used to compile and run
+// the application, picked according to the project's build tool.
+func CommandNames(buildTool string) (compile string, run string) {
+	if buildTool == types.BuildToolGradle {
+		return GradleCompileCmdName, GradleRunCmdName
+	}
+	return CompileCmdName, RunCmdName
+}
+
+// DefaultCommands returns the supervisord program mapping string ("program:
+// command;...") derived from buildTool. It's the single source of truth for
+// "which command actually runs": BuildDeploymentConfig passes it straight to
+// the dev pod's init container, and the dev-s2i ImageStream's "cmds"
+// annotation is stamped with the same value, so the two can never disagree.
+func DefaultCommands(buildTool string) string {
+	compileCmd, runCmd := CommandNames(buildTool)
+	return runCmd + ":/usr/local/s2i/run;" + compileCmd + ":/usr/local/s2i/assemble;build:/deployments/buildapp"
+}
+
 type Tool struct {
 	Application types.Application
 	KubeConfig  Kube
@@ -19,7 +19,15 @@ type Command struct {
 	Format string
 }
 
+// OcBinEnvVar overrides the location of the oc binary, for environments
+// where it isn't on $PATH.
+const OcBinEnvVar = "OC_BIN"
+
 func getClientPath() string {
+	if ocPath, ok := os.LookupEnv(OcBinEnvVar); ok {
+		return ocPath
+	}
+
 	// Search for oc client
 	ocpath, err := exec.LookPath("oc")
 	if err != nil {
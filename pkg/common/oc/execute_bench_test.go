@@ -0,0 +1,33 @@
+package oc
+
+import (
+	"os/exec"
+	"testing"
+)
+
+// BenchmarkOcExec and BenchmarkNoopExec quantify the overhead this
+// request is about: Exists/GetNamesByLabel (and every other oc.* call)
+// fork+exec the oc binary and pay its full startup cost on every call,
+// where the typed-client Get calls this request introduces reuse one
+// already-authenticated connection instead. BenchmarkOcExec runs oc itself
+// (a no-cluster-contact client-only subcommand, so it's safe without a live
+// cluster); BenchmarkNoopExec spawns `true` as a bare fork+exec baseline
+// with none of oc's own startup work. The gap between the two is oc's
+// per-call overhead on top of what any subprocess costs.
+func BenchmarkOcExec(b *testing.B) {
+	if _, err := exec.LookPath("oc"); err != nil {
+		b.Skip("oc binary not on $PATH")
+	}
+	for i := 0; i < b.N; i++ {
+		_, _ = ExecCommandAndReturn(Command{Args: []string{"version", "--client"}})
+	}
+}
+
+func BenchmarkNoopExec(b *testing.B) {
+	if _, err := exec.LookPath("true"); err != nil {
+		b.Skip("true binary not on $PATH")
+	}
+	for i := 0; i < b.N; i++ {
+		_ = exec.Command("true").Run()
+	}
+}
@@ -20,6 +20,18 @@ type Program struct {
 	Command string
 }
 
+// TemplateData is the top-level context handed to supervisord.tmpl: the
+// per-command programs, keyed the same way CMDS splits them, plus the
+// control port supervisord's [inet_http_server] listens on.
+type TemplateData struct {
+	Programs map[string][]Program
+	Port     string
+}
+
+// defaultPort is used when PORT isn't set, matching supervisord's
+// historical hardcoded port so existing images keep working unchanged.
+const defaultPort = "9001"
+
 func main() {
 	// Read Supervisord.tmpl file
 	log.Println("Read Supervisord.tmpl file")
@@ -52,6 +64,12 @@ func main() {
 		log.Fatal("No commands provided !")
 	}
 
+	// Recuperate the control port, defaulting to the historical 9001
+	port := os.Getenv("PORT")
+	if port == "" {
+		port = defaultPort
+	}
+
 	// Create a template to parse supervisord file
 	log.Println("Create a template to parse supervisord file")
 	t := template.New("Supervisord template")
@@ -70,7 +88,7 @@ func main() {
 
 	// Write template result to the supervisord.conf
 	log.Println("Parse template file and generate result")
-	error := t.Execute(outFile, m)
+	error := t.Execute(outFile, TemplateData{Programs: m, Port: port})
 	if error != nil {
 		log.Fatal(error)
 	}